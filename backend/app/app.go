@@ -1,25 +1,183 @@
 package app
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log/slog"
-	"net/http"
+	"net"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 
 	"gitlab.com/paradaise1/t1-hackaton-terraform/config"
+	"gitlab.com/paradaise1/t1-hackaton-terraform/plugin"
 	"gitlab.com/paradaise1/t1-hackaton-terraform/repos"
+	"google.golang.org/grpc"
 )
 
-func Run() error {
-	conf, err := config.ReadConfig("appconfig.yml")
+// parseFlags разбирает --config, --print-config и переопределения настроек
+// вида --retention.max_age=24h (по одному флагу на ключ конфигурации).
+func parseFlags(args []string) (opts config.LoadOptions, printConfig bool, err error) {
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	fs.StringVar(&opts.Path, "config", "", "path to config file (env TFLOGS_CONFIG, default "+config.DefaultPath+")")
+	fs.BoolVar(&printConfig, "print-config", false, "print effective configuration and exit")
+	opts.Overrides = make(map[string]string)
+	for _, key := range config.Keys() {
+		fs.Func(key.Name, fmt.Sprintf("%s (env %s)", key.Type, key.Env), func(v string) error {
+			opts.Overrides[key.Name] = v
+			return nil
+		})
+	}
+	if err := fs.Parse(args); err != nil {
+		return opts, false, err
+	}
+	if fs.NArg() > 0 {
+		return opts, false, fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+	if opts.Path == "" {
+		opts.Path = os.Getenv("TFLOGS_CONFIG")
+	}
+	opts.Required = opts.Path != ""
+	return opts, printConfig, nil
+}
+
+func Run(args []string) error {
+	opts, printConfig, err := parseFlags(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "%s\nusing default config\n", err.Error())
+		return err
 	}
+	conf, err := config.Load(opts)
+	if err != nil {
+		return err
+	}
+	if printConfig {
+		out, err := conf.Redacted().YAML()
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(out)
+		return err
+	}
+	config.Set(conf)
+	setLogLevel(conf.LogLevel)
 
-	server := http.Server{
-		Addr:    conf.Addr,
-		Handler: NewRouter(repos.NewLogRepo()),
+	auth, err := NewAuth(conf.Auth)
+	if err != nil {
+		return err
+	}
+	plugins, err := plugin.NewManager(conf.Plugins.Dir, conf.Plugins.Timeout)
+	if err != nil {
+		return err
+	}
+	defer plugins.Close()
+	var repoOpts []repos.Option
+	if conf.Plugins.Dir != "" {
+		repoOpts = append(repoOpts, repos.WithProcessor(plugins))
+	}
+	repo := repos.NewLogRepo(repoOpts...)
+	janitor, err := NewJanitor(repo, conf.Retention)
+	if err != nil {
+		return err
+	}
+	snapshots := NewSnapshots(repo, conf.Snapshot)
+	if file, err := snapshots.RestoreLatest(context.Background()); err != nil {
+		return err
+	} else if file != "" {
+		slog.Info("state restored", "snapshot", file)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	janitorDone := make(chan struct{})
+	go func() {
+		janitor.Run(ctx)
+		close(janitorDone)
+	}()
+
+	rl := &reloader{opts: opts, auth: auth, janitor: janitor}
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	go func() {
+		for range hup {
+			rl.reload()
+		}
+	}()
+
+	// jobs — текущие загрузки; при остановке их дожидаются до снимка
+	var jobs sync.WaitGroup
+	server, err := newServer(conf, NewRouter(repo, auth, janitor, snapshots, plugins, &jobs))
+	if err != nil {
+		return err
+	}
+	errc := make(chan error, 2)
+	go func() {
+		slog.Info("server started", "addr", conf.Addr, "tls", conf.Server.TLS.Enabled())
+		errc <- serve(server, conf.Server.TLS)
+	}()
+	var grpcServer *grpc.Server
+	if conf.GRPC.Addr != "" {
+		lis, err := net.Listen("tcp", conf.GRPC.Addr)
+		if err != nil {
+			return fmt.Errorf("grpc: %w", err)
+		}
+		tlsConf, err := grpcTLSConfig(conf.Server.TLS, server)
+		if err != nil {
+			return fmt.Errorf("grpc: %w", err)
+		}
+		// Tail завершается по ctx.Done(), иначе GracefulStop ждал бы его вечно
+		grpcServer = newGRPCServer(repo, auth, &jobs, ctx.Done(), tlsConf)
+		go func() {
+			slog.Info("grpc server started", "addr", conf.GRPC.Addr, "tls", tlsConf != nil)
+			errc <- grpcServer.Serve(lis)
+		}()
+	}
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	// Повторный сигнал завершает процесс сразу
+	stop()
+
+	slog.Info("shutting down, draining connections", "timeout", conf.Server.ShutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), conf.Server.ShutdownTimeout)
+	defer cancel()
+	grpcStopped := make(chan struct{})
+	if grpcServer != nil {
+		go func() {
+			grpcServer.GracefulStop()
+			close(grpcStopped)
+		}()
+	}
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("graceful shutdown timed out, closing connections", "err", err)
+		server.Close()
+	}
+	if grpcServer != nil {
+		select {
+		case <-grpcStopped:
+		case <-shutdownCtx.Done():
+			slog.Error("grpc graceful stop timed out, closing connections")
+			grpcServer.Stop()
+		}
+	}
+	jobs.Wait()
+	<-janitorDone
+	// Снимок после остановки сервера — новых загрузок уже не будет
+	if snapshots.Enabled() {
+		info, err := snapshots.Save(context.Background())
+		if err != nil {
+			return fmt.Errorf("snapshot on shutdown: %w", err)
+		}
+		slog.Info("snapshot saved", "file", info.File, "bytes", info.Bytes)
 	}
-	slog.Info("server started", "addr", conf.Addr)
-	return server.ListenAndServe()
+	slog.Info("server stopped")
+	return nil
 }
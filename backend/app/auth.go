@@ -0,0 +1,314 @@
+package app
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"gitlab.com/paradaise1/t1-hackaton-terraform/config"
+	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type Role int
+
+const (
+	RoleViewer Role = iota + 1
+	RoleUploader
+	RoleAdmin
+)
+
+func ParseRole(s string) (Role, error) {
+	switch strings.ToLower(s) {
+	case "viewer":
+		return RoleViewer, nil
+	case "uploader":
+		return RoleUploader, nil
+	case "admin":
+		return RoleAdmin, nil
+	}
+	return 0, fmt.Errorf("unknown role %q", s)
+}
+
+func (r Role) String() string {
+	switch r {
+	case RoleViewer:
+		return "viewer"
+	case RoleUploader:
+		return "uploader"
+	case RoleAdmin:
+		return "admin"
+	}
+	return "none"
+}
+
+func (r Role) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+type User struct {
+	Name       string   `json:"name"`
+	Role       Role     `json:"role"`
+	Workspaces []string `json:"workspaces,omitempty"`
+}
+
+// CanAccess сообщает, может ли пользователь работать в пространстве ws.
+func (u User) CanAccess(ws string) bool {
+	if u.Role == RoleAdmin || slices.Contains(u.Workspaces, "*") {
+		return true
+	}
+	if len(u.Workspaces) == 0 {
+		return ws == log.DefaultWorkspace
+	}
+	return slices.Contains(u.Workspaces, ws)
+}
+
+// DefaultWorkspace — пространство запросов без явного X-Workspace.
+func (u User) DefaultWorkspace() string {
+	if len(u.Workspaces) == 0 || u.Role == RoleAdmin || u.Workspaces[0] == "*" {
+		return log.DefaultWorkspace
+	}
+	return u.Workspaces[0]
+}
+
+type userCtxKey struct{}
+
+func UserFromContext(ctx context.Context) (User, bool) {
+	u, ok := ctx.Value(userCtxKey{}).(User)
+	return u, ok
+}
+
+const sessionCookie = "tflogs_session"
+
+type user struct {
+	User
+	passwordHash []byte
+}
+
+// Auth проверяет API-токены (Authorization: Bearer) и подписанные cookie
+// сессий. С auth.disabled аутентификация выключена и все запросы
+// выполняются с правами admin. Пользователей можно перечитать через Reload
+// без перезапуска.
+type Auth struct {
+	state atomic.Pointer[authState]
+}
+
+type authState struct {
+	enabled bool
+	secret  []byte
+	ttl     time.Duration
+	users   map[string]user // имя -> пользователь
+	tokens  map[string]string
+}
+
+func NewAuth(conf config.AuthConfig) (*Auth, error) {
+	a := &Auth{}
+	if err := a.Reload(conf); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Reload заменяет пользователей, токены и параметры сессий. Если секрет не
+// изменился, выданные ранее cookie остаются действительными.
+func (a *Auth) Reload(conf config.AuthConfig) error {
+	s := &authState{
+		enabled: !conf.Disabled,
+		secret:  []byte(conf.Secret),
+		ttl:     conf.SessionTTL,
+		users:   make(map[string]user),
+		tokens:  make(map[string]string),
+	}
+	if s.ttl <= 0 {
+		s.ttl = 12 * time.Hour
+	}
+	if !s.enabled {
+		slog.Warn("auth disabled: every request, including gRPC, runs as anonymous admin")
+		a.state.Store(s)
+		return nil
+	}
+	if len(s.secret) == 0 {
+		// Случайный ключ переживает перезагрузку конфига, но не перезапуск
+		if old := a.state.Load(); old != nil && old.enabled {
+			s.secret = old.secret
+		} else {
+			slog.Warn("auth.secret is empty, sessions will not survive restart")
+			s.secret = make([]byte, 32)
+			rand.Read(s.secret)
+		}
+	}
+	for _, u := range conf.Users {
+		role, err := ParseRole(u.Role)
+		if err != nil {
+			return fmt.Errorf("user %q: %w", u.Name, err)
+		}
+		if u.Name == "" {
+			return errors.New("user without name")
+		}
+		for _, ws := range u.Workspaces {
+			if ws != "*" && !log.ValidWorkspaceID(ws) {
+				return fmt.Errorf("user %q: invalid workspace %q", u.Name, ws)
+			}
+		}
+		s.users[u.Name] = user{
+			User:         User{Name: u.Name, Role: role, Workspaces: u.Workspaces},
+			passwordHash: []byte(u.PasswordHash),
+		}
+		for _, t := range u.Tokens {
+			s.tokens[t] = u.Name
+		}
+	}
+	a.state.Store(s)
+	return nil
+}
+
+func (a *Auth) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !a.state.Load().enabled {
+			u := User{Name: "anonymous", Role: RoleAdmin}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userCtxKey{}, u)))
+			return
+		}
+		if u, ok := a.authenticate(r); ok {
+			r = r.WithContext(context.WithValue(r.Context(), userCtxKey{}, u))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (a *Auth) authenticate(r *http.Request) (User, bool) {
+	s := a.state.Load()
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return s.tokenUser(token)
+	}
+	if c, err := r.Cookie(sessionCookie); err == nil {
+		if name, ok := s.verifySession(c.Value); ok {
+			if u, ok := s.users[name]; ok {
+				return u.User, true
+			}
+		}
+	}
+	return User{}, false
+}
+
+// authorize возвращает пользователя по значению заголовка Authorization
+// (для gRPC, где нет cookie); с auth.disabled — анонимный admin.
+func (a *Auth) authorize(header string) (User, bool) {
+	s := a.state.Load()
+	if !s.enabled {
+		return User{Name: "anonymous", Role: RoleAdmin}, true
+	}
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok {
+		return User{}, false
+	}
+	return s.tokenUser(token)
+}
+
+func (s *authState) tokenUser(token string) (User, bool) {
+	for t, name := range s.tokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			return s.users[name].User, true
+		}
+	}
+	return User{}, false
+}
+
+// RequireRole пропускает запрос, если роль пользователя не ниже role.
+func RequireRole(role Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			u, ok := UserFromContext(r.Context())
+			if !ok {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			if u.Role < role {
+				http.Error(w, "forbidden", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Login проверяет пароль и выдаёт cookie сессии; по HTTPS — с флагом Secure.
+func (a *Auth) Login(w http.ResponseWriter, r *http.Request, name, password string) (User, bool) {
+	s := a.state.Load()
+	u, ok := s.users[name]
+	if !ok || len(u.passwordHash) == 0 ||
+		bcrypt.CompareHashAndPassword(u.passwordHash, []byte(password)) != nil {
+		return User{}, false
+	}
+	expires := time.Now().Add(s.ttl)
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    s.signSession(name, expires),
+		Path:     "/",
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return u.User, true
+}
+
+func (a *Auth) Logout(w http.ResponseWriter, r *http.Request) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// Сессия: base64(имя).unix-время-истечения.base64(HMAC-SHA256)
+func (s *authState) signSession(name string, expires time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(name)) + "." + strconv.FormatInt(expires.Unix(), 10)
+	return payload + "." + s.sign(payload)
+}
+
+func (s *authState) verifySession(value string) (string, bool) {
+	i := strings.LastIndexByte(value, '.')
+	if i < 0 {
+		return "", false
+	}
+	payload, sig := value[:i], value[i+1:]
+	if !hmac.Equal([]byte(sig), []byte(s.sign(payload))) {
+		return "", false
+	}
+	encName, exp, ok := strings.Cut(payload, ".")
+	if !ok {
+		return "", false
+	}
+	expUnix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || time.Now().Unix() > expUnix {
+		return "", false
+	}
+	name, err := base64.RawURLEncoding.DecodeString(encName)
+	if err != nil {
+		return "", false
+	}
+	return string(name), true
+}
+
+func (s *authState) sign(payload string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
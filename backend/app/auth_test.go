@@ -0,0 +1,214 @@
+package app
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/paradaise1/t1-hackaton-terraform/config"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func testAuthConfig(t *testing.T) config.AuthConfig {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte("pw"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return config.AuthConfig{
+		Secret:     "cookie-key",
+		SessionTTL: time.Hour,
+		Users: []config.UserConfig{
+			{Name: "viewer", Role: "viewer", Tokens: []string{"tok-viewer"}},
+			{Name: "ci", Role: "uploader", Tokens: []string{"tok-ci"}, Workspaces: []string{"team-a"}},
+			{Name: "root", Role: "admin", PasswordHash: string(hash)},
+		},
+	}
+}
+
+func newTestAuth(t *testing.T, conf config.AuthConfig) *Auth {
+	t.Helper()
+	a, err := NewAuth(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+// serveAuth пропускает запрос через Middleware и RequireRole(role).
+func serveAuth(a *Auth, role Role, r *http.Request) int {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	w := httptest.NewRecorder()
+	a.Middleware(RequireRole(role)(ok)).ServeHTTP(w, r)
+	return w.Code
+}
+
+func TestRequireRole(t *testing.T) {
+	a := newTestAuth(t, testAuthConfig(t))
+	tests := []struct {
+		auth string
+		role Role
+		want int
+	}{
+		{"", RoleViewer, http.StatusUnauthorized},
+		{"Bearer nope", RoleViewer, http.StatusUnauthorized},
+		{"Basic tok-viewer", RoleViewer, http.StatusUnauthorized},
+		{"Bearer tok-viewer", RoleViewer, http.StatusOK},
+		{"Bearer tok-viewer", RoleUploader, http.StatusForbidden},
+		{"Bearer tok-ci", RoleViewer, http.StatusOK},
+		{"Bearer tok-ci", RoleUploader, http.StatusOK},
+		{"Bearer tok-ci", RoleAdmin, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tt.auth != "" {
+			r.Header.Set("Authorization", tt.auth)
+		}
+		if got := serveAuth(a, tt.role, r); got != tt.want {
+			t.Errorf("%q for %s: status %d, want %d", tt.auth, tt.role, got, tt.want)
+		}
+	}
+
+	disabled := newTestAuth(t, config.AuthConfig{Disabled: true})
+	if got := serveAuth(disabled, RoleAdmin, httptest.NewRequest(http.MethodGet, "/", nil)); got != http.StatusOK {
+		t.Errorf("auth disabled: status %d, want 200", got)
+	}
+}
+
+func TestAuthorizeGRPC(t *testing.T) {
+	a := newTestAuth(t, testAuthConfig(t))
+	if u, ok := a.authorize("Bearer tok-ci"); !ok || u.Name != "ci" || u.Role != RoleUploader {
+		t.Errorf("token: %+v %v", u, ok)
+	}
+	for _, header := range []string{"", "tok-ci", "Bearer tok-c"} {
+		if _, ok := a.authorize(header); ok {
+			t.Errorf("authorize(%q) succeeded", header)
+		}
+	}
+	disabled := newTestAuth(t, config.AuthConfig{Disabled: true})
+	if u, ok := disabled.authorize(""); !ok || u.Role != RoleAdmin {
+		t.Errorf("auth disabled: %+v %v", u, ok)
+	}
+}
+
+func login(t *testing.T, a *Auth, secure bool) *http.Cookie {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodPost, "/login", nil)
+	if secure {
+		r.TLS = &tls.ConnectionState{}
+	}
+	w := httptest.NewRecorder()
+	if _, ok := a.Login(w, r, "root", "pw"); !ok {
+		t.Fatal("login failed")
+	}
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != sessionCookie {
+		t.Fatalf("cookies = %v", cookies)
+	}
+	return cookies[0]
+}
+
+func withCookie(c *http.Cookie) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(c)
+	return r
+}
+
+func TestSessionCookie(t *testing.T) {
+	conf := testAuthConfig(t)
+	a := newTestAuth(t, conf)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/login", nil)
+	if _, ok := a.Login(w, r, "root", "wrong"); ok || len(w.Result().Cookies()) != 0 {
+		t.Error("login with wrong password succeeded")
+	}
+	if _, ok := a.Login(w, r, "ci", ""); ok {
+		t.Error("login of a token-only user succeeded")
+	}
+
+	c := login(t, a, false)
+	if c.Secure || !c.HttpOnly {
+		t.Errorf("plain HTTP cookie: secure %v, httponly %v", c.Secure, c.HttpOnly)
+	}
+	if !login(t, a, true).Secure {
+		t.Error("HTTPS cookie without Secure")
+	}
+	if got := serveAuth(a, RoleAdmin, withCookie(c)); got != http.StatusOK {
+		t.Errorf("valid session: status %d", got)
+	}
+
+	// Подмена имени без новой подписи
+	parts := strings.Split(c.Value, ".")
+	forged := *c
+	forged.Value = "dmlld2Vy." + parts[1] + "." + parts[2]
+	if got := serveAuth(a, RoleViewer, withCookie(&forged)); got != http.StatusUnauthorized {
+		t.Errorf("forged session: status %d", got)
+	}
+	expired := *c
+	expired.Value = a.state.Load().signSession("root", time.Now().Add(-time.Minute))
+	if got := serveAuth(a, RoleViewer, withCookie(&expired)); got != http.StatusUnauthorized {
+		t.Errorf("expired session: status %d", got)
+	}
+	for _, value := range []string{"", "garbage", "a.b", "cm9vdA.x.y"} {
+		bad := *c
+		bad.Value = value
+		if got := serveAuth(a, RoleViewer, withCookie(&bad)); got != http.StatusUnauthorized {
+			t.Errorf("session %q: status %d", value, got)
+		}
+	}
+
+	// Тот же секрет — сессии переживают перезагрузку
+	if err := a.Reload(conf); err != nil {
+		t.Fatal(err)
+	}
+	if got := serveAuth(a, RoleAdmin, withCookie(c)); got != http.StatusOK {
+		t.Errorf("session after reload: status %d", got)
+	}
+	conf.Secret = "rotated"
+	if err := a.Reload(conf); err != nil {
+		t.Fatal(err)
+	}
+	if got := serveAuth(a, RoleViewer, withCookie(c)); got != http.StatusUnauthorized {
+		t.Errorf("session after secret rotation: status %d", got)
+	}
+
+	w = httptest.NewRecorder()
+	a.Logout(w, httptest.NewRequest(http.MethodPost, "/logout", nil))
+	if cookies := w.Result().Cookies(); len(cookies) != 1 || cookies[0].MaxAge >= 0 {
+		t.Errorf("logout cookies = %v", cookies)
+	}
+}
+
+func TestUserWorkspaces(t *testing.T) {
+	tests := []struct {
+		user     User
+		ws       string
+		access   bool
+		fallback string
+	}{
+		{User{Role: RoleViewer}, "default", true, "default"},
+		{User{Role: RoleViewer}, "team-a", false, "default"},
+		{User{Role: RoleUploader, Workspaces: []string{"team-a", "team-b"}}, "team-b", true, "team-a"},
+		{User{Role: RoleUploader, Workspaces: []string{"team-a"}}, "default", false, "team-a"},
+		{User{Role: RoleViewer, Workspaces: []string{"*"}}, "team-z", true, "default"},
+		{User{Role: RoleAdmin, Workspaces: []string{"team-a"}}, "team-z", true, "default"},
+	}
+	for _, tt := range tests {
+		if got := tt.user.CanAccess(tt.ws); got != tt.access {
+			t.Errorf("%+v CanAccess(%s) = %v", tt.user, tt.ws, got)
+		}
+		if got := tt.user.DefaultWorkspace(); got != tt.fallback {
+			t.Errorf("%+v DefaultWorkspace() = %s, want %s", tt.user, got, tt.fallback)
+		}
+	}
+	if _, err := ParseRole("owner"); err == nil {
+		t.Error("ParseRole(owner) succeeded")
+	}
+	if r, err := ParseRole("Admin"); err != nil || r != RoleAdmin || r.String() != "admin" {
+		t.Errorf("ParseRole(Admin) = %v, %v", r, err)
+	}
+}
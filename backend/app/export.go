@@ -0,0 +1,66 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+	"gitlab.com/paradaise1/t1-hackaton-terraform/plugin"
+)
+
+// exportPlugin выгружает отфильтрованные записи форматом плагина.
+func exportPlugin(w http.ResponseWriter, r *http.Request, repo log.Repo, plugins *plugin.Manager, format string, filters log.ExportFilters) {
+	logs, err := repo.GetLogs(r.Context(), filters)
+	if errors.Is(err, log.ErrInvalid) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "export failed", http.StatusInternalServerError)
+		return
+	}
+	data, f, err := plugins.Export(r.Context(), format, logs)
+	if errors.Is(err, plugin.ErrUnknownFormat) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		slog.Error("plugin export failed", "format", format, "err", err)
+		http.Error(w, "export failed", http.StatusBadGateway)
+		return
+	}
+	ext := f.Extension
+	if ext == "" {
+		ext = f.Name
+	}
+	contentType := f.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=logs_export.%s", ext))
+	w.Header().Set("Content-Type", contentType)
+	w.Write(data)
+}
+
+// exportHAR выгружает HTTP-транзакции отфильтрованных записей в HAR 1.2.
+func exportHAR(w http.ResponseWriter, r *http.Request, repo log.Repo, filters log.ExportFilters) {
+	transactions, err := repo.GetHTTPTransactions(r.Context(), filters)
+	if errors.Is(err, log.ErrInvalid) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "export failed", http.StatusInternalServerError)
+		return
+	}
+	data, err := log.HAR(transactions)
+	if err != nil {
+		http.Error(w, "export failed", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Disposition", "attachment; filename=logs_export.har")
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
@@ -0,0 +1,121 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"gitlab.com/paradaise1/t1-hackaton-terraform/config"
+	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+)
+
+// gateRequest переопределяет пороги из конфигурации для одного запроса;
+// незаданные поля берутся из раздела gate.
+type gateRequest struct {
+	MaxErrors       *int   `json:"max_errors"`
+	MaxHTTP5xx      *int   `json:"max_http_5xx"`
+	MaxRetries      *int   `json:"max_retries"`
+	MaxRPCDuration  string `json:"max_rpc_duration"`
+	MaxHTTPDuration string `json:"max_http_duration"`
+}
+
+func gateThresholds(conf config.GateConfig) log.GateThresholds {
+	return log.GateThresholds{
+		MaxErrors:       conf.MaxErrors,
+		MaxHTTP5xx:      conf.MaxHTTP5xx,
+		MaxRetries:      conf.MaxRetries,
+		MaxRPCDuration:  conf.MaxRPCDuration,
+		MaxHTTPDuration: conf.MaxHTTPDuration,
+	}
+}
+
+func readGateThresholds(r *http.Request) (log.GateThresholds, error) {
+	t := gateThresholds(config.Get().Gate)
+	var req gateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		return t, err
+	}
+	for _, v := range []struct {
+		dst *int
+		src *int
+	}{
+		{&t.MaxErrors, req.MaxErrors},
+		{&t.MaxHTTP5xx, req.MaxHTTP5xx},
+		{&t.MaxRetries, req.MaxRetries},
+	} {
+		if v.src != nil {
+			*v.dst = *v.src
+		}
+	}
+	for _, v := range []struct {
+		name string
+		dst  *time.Duration
+		src  string
+	}{
+		{"max_rpc_duration", &t.MaxRPCDuration, req.MaxRPCDuration},
+		{"max_http_duration", &t.MaxHTTPDuration, req.MaxHTTPDuration},
+	} {
+		if v.src == "" {
+			continue
+		}
+		d, err := time.ParseDuration(v.src)
+		if err != nil || d < 0 {
+			return t, fmt.Errorf("invalid %s", v.name)
+		}
+		*v.dst = d
+	}
+	return t, nil
+}
+
+// wantsJUnit — отчёт в JUnit XML по ?format=junit или Accept: application/xml.
+func wantsJUnit(r *http.Request) bool {
+	if f := r.URL.Query().Get("format"); f != "" {
+		return f == "junit"
+	}
+	accept := r.Header.Get("Accept")
+	return strings.Contains(accept, "application/xml") || strings.Contains(accept, "text/xml")
+}
+
+// gateHandler отвечает 200, если загрузка прошла проверку, и 422 с тем же
+// отчётом, если нет: в CI достаточно curl --fail-with-body.
+func gateHandler(repo log.Repo) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		thresholds, err := readGateThresholds(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		report, err := repo.EvaluateGate(r.Context(), chi.URLParam(r, "id"), thresholds)
+		if errors.Is(err, log.ErrNotFound) {
+			http.Error(w, "upload not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, "failed to evaluate gate", http.StatusInternalServerError)
+			return
+		}
+		status := http.StatusOK
+		if !report.Passed {
+			status = http.StatusUnprocessableEntity
+		}
+		if !wantsJUnit(r) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(report)
+			return
+		}
+		out, err := report.JUnit()
+		if err != nil {
+			http.Error(w, "failed to render report", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(status)
+		w.Write(out)
+	}
+}
@@ -0,0 +1,313 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+
+	"gitlab.com/paradaise1/t1-hackaton-terraform/config"
+	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+	tflogsv1 "gitlab.com/paradaise1/t1-hackaton-terraform/gen/tflogs/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// grpcRoles — минимальная роль для методов; остальным достаточно viewer.
+var grpcRoles = map[string]Role{
+	tflogsv1.LogService_Ingest_FullMethodName: RoleUploader,
+}
+
+// grpcService реализует tflogsv1.LogService поверх того же log.Repo, что и
+// REST. done закрывается при остановке сервера, чтобы завершить Tail.
+type grpcService struct {
+	tflogsv1.UnimplementedLogServiceServer
+	repo log.Repo
+	jobs *sync.WaitGroup
+	done <-chan struct{}
+}
+
+func newGRPCServer(repo log.Repo, auth *Auth, jobs *sync.WaitGroup, done <-chan struct{}, tlsConf *tls.Config) *grpc.Server {
+	var opts []grpc.ServerOption
+	if tlsConf != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConf)))
+	}
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			ctx, err := grpcAuthorize(ctx, repo, auth, info.FullMethod)
+			if err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.ChainStreamInterceptor(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			ctx, err := grpcAuthorize(ss.Context(), repo, auth, info.FullMethod)
+			if err != nil {
+				return err
+			}
+			return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+		}),
+	)
+	server := grpc.NewServer(opts...)
+	tflogsv1.RegisterLogServiceServer(server, &grpcService{repo: repo, jobs: jobs, done: done})
+	return server
+}
+
+// grpcAuthorize — аналог Auth.Middleware, RequireRole и WorkspaceMiddleware:
+// токен из authorization, пространство из x-workspace.
+func grpcAuthorize(ctx context.Context, repo log.Repo, auth *Auth, method string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	first := func(key string) string {
+		if v := md.Get(key); len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+	u, ok := auth.authorize(first("authorization"))
+	if !ok {
+		return ctx, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+	if u.Role < max(RoleViewer, grpcRoles[method]) {
+		return ctx, status.Error(codes.PermissionDenied, "forbidden")
+	}
+	ctx = context.WithValue(ctx, userCtxKey{}, u)
+	ctx, err := selectWorkspace(ctx, repo, u, first("x-workspace"))
+	if errors.Is(err, errWorkspaceForbidden) {
+		return ctx, status.Error(codes.PermissionDenied, "workspace forbidden")
+	}
+	if err != nil {
+		return ctx, status.Error(codes.NotFound, "workspace not found")
+	}
+	return ctx, nil
+}
+
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// grpcError переводит ошибки хранилища в коды gRPC.
+func grpcError(err error) error {
+	switch {
+	case errors.Is(err, log.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, log.ErrInvalid):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, log.ErrQuotaExceeded):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func (s *grpcService) Ingest(stream tflogsv1.LogService_IngestServer) error {
+	s.jobs.Add(1)
+	defer s.jobs.Done()
+
+	limit := config.Get().HTTP.MaxUploadBytes
+	var (
+		buf  bytes.Buffer
+		opts log.UploadOptions
+	)
+	for first := true; ; first = false {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if first {
+			opts = log.UploadOptions{FileName: req.GetFileName(), TFWorkspace: req.GetTfWorkspace(), RunID: req.GetRunId()}
+		}
+		for _, line := range req.GetLines() {
+			buf.WriteString(strings.TrimRight(line, "\r\n"))
+			buf.WriteByte('\n')
+		}
+		if int64(buf.Len()) > limit {
+			return status.Errorf(codes.ResourceExhausted, "upload exceeds %d bytes", limit)
+		}
+	}
+	if buf.Len() == 0 {
+		return status.Error(codes.InvalidArgument, "no lines")
+	}
+	if opts.FileName == "" {
+		opts.FileName = "grpc-ingest.json"
+	}
+	res, err := s.repo.UploadFile(stream.Context(), buf.Bytes(), opts)
+	if err != nil {
+		return grpcError(err)
+	}
+	return stream.SendAndClose(&tflogsv1.IngestResponse{
+		Id:          res.ID,
+		Status:      res.Status,
+		DuplicateOf: res.DuplicateOf,
+		TfWorkspace: res.TFWorkspace,
+		Lines:       int32(res.Lines),
+		Corrupted:   int32(res.Corrupted),
+		Duplicates:  int32(res.Duplicates),
+		Novel:       toFingerprints(res.Novel),
+		Disappeared: toFingerprints(res.Disappeared),
+	})
+}
+
+func (s *grpcService) Query(req *tflogsv1.QueryRequest, stream tflogsv1.LogService_QueryServer) error {
+	filters := log.ExportFilters{
+		TFResourceType: req.GetTfResourceType(),
+		TimestampFrom:  req.GetTimestampFrom(),
+		TimestampTo:    req.GetTimestampTo(),
+		Level:          req.GetLevel(),
+		Search:         req.GetSearch(),
+		Query:          req.GetQuery(),
+		TemplateID:     req.GetTemplateId(),
+		TFReqID:        req.GetTfReqId(),
+		State:          req.GetState(),
+		Assignee:       req.GetAssignee(),
+	}
+	// Один срез на весь поток: постраничный обход пересортировывал бы всё
+	// хранилище на каждой странице, а загрузки между страницами сдвигали бы их
+	logs, err := s.repo.GetAllLogs(stream.Context(), filters)
+	if err != nil {
+		return grpcError(err)
+	}
+	if n := int(req.GetLimit()); n > 0 && n < len(logs) {
+		logs = logs[:n]
+	}
+	for _, l := range logs {
+		if err := stream.Send(&tflogsv1.QueryResponse{Entry: toLogEntry(l)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *grpcService) Tail(req *tflogsv1.TailRequest, stream tflogsv1.LogService_TailServer) error {
+	q, err := log.ParseQuery(req.GetQuery())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	events, err := s.repo.Subscribe(ctx)
+	if err != nil {
+		return grpcError(err)
+	}
+	for {
+		select {
+		case <-s.done:
+			return status.Error(codes.Unavailable, "server is shutting down")
+		case ev, ok := <-events:
+			if !ok {
+				return stream.Context().Err()
+			}
+			for _, l := range ev.Logs {
+				if !q.Match(&l) {
+					continue
+				}
+				if err := stream.Send(&tflogsv1.TailResponse{Entry: toLogEntry(l)}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+func (s *grpcService) GetGroup(ctx context.Context, req *tflogsv1.GetGroupRequest) (*tflogsv1.GetGroupResponse, error) {
+	if req.GetTfReqId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "tf_req_id required")
+	}
+	logs, err := s.repo.GetGroupByReqID(ctx, req.GetTfReqId())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	res := &tflogsv1.GetGroupResponse{Entries: make([]*tflogsv1.LogEntry, 0, len(logs))}
+	for _, l := range logs {
+		res.Entries = append(res.Entries, toLogEntry(l))
+	}
+	return res, nil
+}
+
+func (s *grpcService) GetTimeline(ctx context.Context, _ *tflogsv1.GetTimelineRequest) (*tflogsv1.GetTimelineResponse, error) {
+	entries, err := s.repo.GetTimelineEntries(ctx)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	res := &tflogsv1.GetTimelineResponse{Entries: make([]*tflogsv1.TimelineEntry, 0, len(entries))}
+	for _, e := range entries {
+		res.Entries = append(res.Entries, &tflogsv1.TimelineEntry{
+			TfReqId: e.TFReqID,
+			Start:   e.Start,
+			End:     e.End,
+			Status:  e.Status,
+		})
+	}
+	return res, nil
+}
+
+func (s *grpcService) GetMetrics(ctx context.Context, _ *tflogsv1.GetMetricsRequest) (*tflogsv1.GetMetricsResponse, error) {
+	m, err := s.repo.GetMetrics(ctx)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	res := &tflogsv1.GetMetricsResponse{
+		Errors:   int32(m.Errors),
+		Warnings: int32(m.Warnings),
+		Levels:   make(map[string]int32, len(m.Levels)),
+	}
+	for level, n := range m.Levels {
+		res.Levels[level] = int32(n)
+	}
+	return res, nil
+}
+
+func toLogEntry(l log.Log) *tflogsv1.LogEntry {
+	raw, _ := json.Marshal(l)
+	return &tflogsv1.LogEntry{
+		Id:             l.Id,
+		Timestamp:      l.At_timestamp,
+		Level:          l.At_level,
+		Message:        l.At_message,
+		Module:         l.At_module,
+		Severity:       log.Severity(l),
+		TfReqId:        l.Tf_req_id,
+		TfRpc:          l.Tf_rpc,
+		TfResourceType: l.Tf_resource_type,
+		HttpMethod:     l.Tf_http_req_method,
+		HttpUri:        l.Tf_http_req_uri,
+		HttpStatus:     int32(l.Tf_http_res_status_code),
+		UploadId:       l.UploadID,
+		TemplateId:     l.TemplateID,
+		Json:           raw,
+	}
+}
+
+func toFingerprints(fps []log.ErrorFingerprint) []*tflogsv1.ErrorFingerprint {
+	res := make([]*tflogsv1.ErrorFingerprint, 0, len(fps))
+	for _, f := range fps {
+		res = append(res, &tflogsv1.ErrorFingerprint{
+			Fingerprint:  f.Fingerprint,
+			Severity:     f.Severity,
+			ResourceType: f.ResourceType,
+			TfRpc:        f.TFRPC,
+			TemplateId:   f.TemplateID,
+			Template:     f.Template,
+			ErrorCode:    f.ErrorCode,
+			Summary:      f.Summary,
+			Count:        int32(f.Count),
+			SampleLogId:  f.SampleLogID,
+		})
+	}
+	return res
+}
@@ -0,0 +1,145 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"testing"
+
+	"gitlab.com/paradaise1/t1-hackaton-terraform/config"
+	tflogsv1 "gitlab.com/paradaise1/t1-hackaton-terraform/gen/tflogs/v1"
+	"gitlab.com/paradaise1/t1-hackaton-terraform/repos"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// startGRPC поднимает сервис поверх bufconn; закрытие done имитирует остановку.
+func startGRPC(t *testing.T, done chan struct{}) tflogsv1.LogServiceClient {
+	t.Helper()
+	conf := testAuthConfig(t)
+	conf.Users = append(conf.Users, config.UserConfig{Name: "bot", Role: "uploader", Tokens: []string{"tok-bot"}, Workspaces: []string{"*"}})
+	lis := bufconn.Listen(1 << 20)
+	var jobs sync.WaitGroup
+	server := newGRPCServer(repos.NewLogRepo(), newTestAuth(t, conf), &jobs, done, nil)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return tflogsv1.NewLogServiceClient(conn)
+}
+
+func withToken(token string, kv ...string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), append([]string{"authorization", "Bearer " + token}, kv...)...)
+}
+
+func ingest(ctx context.Context, c tflogsv1.LogServiceClient, lines ...string) (*tflogsv1.IngestResponse, error) {
+	stream, err := c.Ingest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Ошибку отправки получит CloseAndRecv вместе со статусом сервера
+	if err := stream.Send(&tflogsv1.IngestRequest{FileName: "apply.json", Lines: lines}); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return stream.CloseAndRecv()
+}
+
+func grpcLines(n int) []string {
+	var lines []string
+	for i := range n {
+		lines = append(lines, fmt.Sprintf(`{"@level":"info","@message":"line %d","@timestamp":"2025-09-09T15:31:%02d.000000+03:00"}`, i, i))
+	}
+	return lines
+}
+
+func TestGRPCAuthorization(t *testing.T) {
+	c := startGRPC(t, make(chan struct{}))
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want codes.Code
+	}{
+		{"no token", context.Background(), codes.Unauthenticated},
+		{"unknown token", withToken("nope"), codes.Unauthenticated},
+		{"viewer", withToken("tok-viewer"), codes.PermissionDenied},
+		{"foreign workspace", withToken("tok-ci", "x-workspace", "default"), codes.PermissionDenied},
+		{"missing workspace", withToken("tok-ci"), codes.NotFound},
+		{"no lines", withToken("tok-bot"), codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		if _, err := ingest(tt.ctx, c); status.Code(err) != tt.want {
+			t.Errorf("%s: %v, want %s", tt.name, err, tt.want)
+		}
+	}
+	if _, err := c.GetGroup(withToken("tok-viewer"), &tflogsv1.GetGroupRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("GetGroup without tf_req_id: %v", err)
+	}
+}
+
+func TestGRPCIngestQuery(t *testing.T) {
+	c := startGRPC(t, make(chan struct{}))
+	res, err := ingest(withToken("tok-bot"), c, grpcLines(3)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.GetId() == "" || res.GetLines() != 3 {
+		t.Errorf("ingest = %+v", res)
+	}
+	if dup, err := ingest(withToken("tok-bot"), c, grpcLines(3)...); err != nil || dup.GetDuplicateOf() != res.GetId() {
+		t.Errorf("repeated ingest = %+v, %v", dup, err)
+	}
+
+	// Зритель видит загрузку в default, limit обрезает поток
+	stream, err := c.Query(withToken("tok-viewer"), &tflogsv1.QueryRequest{Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, resp.GetEntry().GetMessage())
+	}
+	if len(got) != 2 {
+		t.Errorf("query with limit 2 = %v", got)
+	}
+}
+
+func TestGRPCTailStopsOnShutdown(t *testing.T) {
+	done := make(chan struct{})
+	c := startGRPC(t, done)
+	stream, err := c.Tail(withToken("tok-viewer"), &tflogsv1.TailRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bad, err := c.Tail(withToken("tok-viewer"), &tflogsv1.TailRequest{Query: "(level:error"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bad.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("tail with invalid query: %v", err)
+	}
+
+	close(done)
+	if _, err := stream.Recv(); status.Code(err) != codes.Unavailable {
+		t.Errorf("tail after shutdown: %v", err)
+	}
+}
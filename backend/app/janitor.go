@@ -0,0 +1,126 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"gitlab.com/paradaise1/t1-hackaton-terraform/config"
+	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+)
+
+// Janitor периодически применяет политику хранения к репозиторию и
+// запоминает результат последнего прохода.
+type Janitor struct {
+	repo     log.Repo
+	policy   log.RetentionPolicy
+	interval time.Duration
+
+	mu   sync.Mutex
+	last *log.RetentionReport
+}
+
+func NewJanitor(repo log.Repo, conf config.RetentionConfig) (*Janitor, error) {
+	j := &Janitor{repo: repo}
+	if err := j.Reload(conf); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// Reload заменяет политику хранения; новый интервал действует со
+// следующего прохода.
+func (j *Janitor) Reload(conf config.RetentionConfig) error {
+	evict := log.EvictOrder(conf.Evict)
+	switch evict {
+	case "":
+		evict = log.EvictOldest
+	case log.EvictOldest, log.EvictLRU:
+	default:
+		return fmt.Errorf("retention.evict: unknown order %q", conf.Evict)
+	}
+	interval := conf.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.policy = log.RetentionPolicy{
+		MaxAge:         conf.MaxAge,
+		MaxEntries:     conf.MaxEntries,
+		MaxMemoryBytes: conf.MaxMemoryBytes,
+		Evict:          evict,
+	}
+	j.interval = interval
+	return nil
+}
+
+func (j *Janitor) settings() (log.RetentionPolicy, time.Duration) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.policy, j.interval
+}
+
+func enabled(p log.RetentionPolicy) bool {
+	return p.MaxAge > 0 || p.MaxEntries > 0 || p.MaxMemoryBytes > 0
+}
+
+// Run чистит репозиторий каждые interval до отмены ctx. Без ограничений
+// проходы пропускаются, но цикл продолжается — политику могут включить
+// перезагрузкой конфига.
+func (j *Janitor) Run(ctx context.Context) {
+	for {
+		policy, interval := j.settings()
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+			if enabled(policy) {
+				j.Sweep(ctx)
+			}
+		}
+	}
+}
+
+func (j *Janitor) Sweep(ctx context.Context) (log.RetentionReport, error) {
+	policy, _ := j.settings()
+	report, err := j.repo.ApplyRetention(ctx, policy)
+	if err != nil {
+		slog.Error("retention failed", "err", err)
+		return report, err
+	}
+	for _, e := range report.Evicted {
+		slog.Info("upload evicted", "workspace", e.Workspace, "upload", e.UploadID,
+			"entries", e.Entries, "reason", e.Reason)
+	}
+	j.mu.Lock()
+	j.last = &report
+	j.mu.Unlock()
+	return report, nil
+}
+
+type JanitorStatus struct {
+	Enabled        bool                 `json:"enabled"`
+	MaxAge         string               `json:"max_age"`
+	MaxEntries     int                  `json:"max_entries"`
+	MaxMemoryBytes int64                `json:"max_memory_bytes"`
+	Evict          log.EvictOrder       `json:"evict"`
+	Interval       string               `json:"interval"`
+	Last           *log.RetentionReport `json:"last,omitempty"`
+}
+
+func (j *Janitor) Status() JanitorStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return JanitorStatus{
+		Enabled:        enabled(j.policy),
+		MaxAge:         j.policy.MaxAge.String(),
+		MaxEntries:     j.policy.MaxEntries,
+		MaxMemoryBytes: j.policy.MaxMemoryBytes,
+		Evict:          j.policy.Evict,
+		Interval:       j.interval.String(),
+		Last:           j.last,
+	}
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"log/slog"
+
+	"gitlab.com/paradaise1/t1-hackaton-terraform/config"
+)
+
+// reloader перечитывает конфигурацию по SIGHUP. Безопасные настройки
+// (пользователи, хранение, лимиты HTTP, CORS, уровень логов) применяются
+// сразу, остальные (адреса, сервер, gRPC, снимки, плагины) — только после
+// перезапуска.
+type reloader struct {
+	opts    config.LoadOptions
+	auth    *Auth
+	janitor *Janitor
+}
+
+func (rl *reloader) reload() {
+	next, err := config.Load(rl.opts)
+	if err != nil {
+		slog.Error("config reload failed, keeping current settings", "err", err)
+		return
+	}
+	cur := config.Get()
+	if changed := restartOnly(cur, next); len(changed) > 0 {
+		slog.Warn("settings changed but require restart", "keys", changed)
+		next.Addr = cur.Addr
+		next.Server = cur.Server
+		next.GRPC = cur.GRPC
+		next.Snapshot = cur.Snapshot
+		next.Plugins = cur.Plugins
+	}
+	if err := rl.auth.Reload(next.Auth); err != nil {
+		slog.Error("config reload failed, keeping current settings", "err", err)
+		return
+	}
+	if err := rl.janitor.Reload(next.Retention); err != nil {
+		slog.Error("retention reload failed", "err", err)
+	}
+	setLogLevel(next.LogLevel)
+	config.Set(next)
+	slog.Info("config reloaded")
+}
+
+func restartOnly(cur, next config.Config) []string {
+	var changed []string
+	if cur.Addr != next.Addr {
+		changed = append(changed, "addr")
+	}
+	if cur.Server != next.Server {
+		changed = append(changed, "server")
+	}
+	if cur.GRPC != next.GRPC {
+		changed = append(changed, "grpc")
+	}
+	if cur.Snapshot != next.Snapshot {
+		changed = append(changed, "snapshot")
+	}
+	if cur.Plugins != next.Plugins {
+		changed = append(changed, "plugins")
+	}
+	return changed
+}
+
+func setLogLevel(level string) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err == nil {
+		slog.SetLogLoggerLevel(l)
+	}
+}
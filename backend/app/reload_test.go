@@ -0,0 +1,101 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"gitlab.com/paradaise1/t1-hackaton-terraform/config"
+	"gitlab.com/paradaise1/t1-hackaton-terraform/repos"
+)
+
+func writeConfig(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "appconfig.yml")
+	writeConfig(t, path, `
+addr: 127.0.0.1:8080
+auth:
+  users:
+    - {name: ci, role: uploader, tokens: [tok-old]}
+retention:
+  max_age: 1h
+`)
+	opts := config.LoadOptions{Path: path, Required: true}
+	conf, err := config.Load(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := config.Get()
+	config.Set(conf)
+	t.Cleanup(func() {
+		config.Set(prev)
+		setLogLevel(prev.LogLevel)
+	})
+	auth := newTestAuth(t, conf.Auth)
+	janitor, err := NewJanitor(repos.NewLogRepo(), conf.Retention)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rl := &reloader{opts: opts, auth: auth, janitor: janitor}
+
+	// Адрес требует перезапуска, пользователи и хранение — применяются сразу
+	writeConfig(t, path, `
+addr: 127.0.0.1:9090
+auth:
+  users:
+    - {name: ci, role: uploader, tokens: [tok-new]}
+retention:
+  max_age: 2h
+`)
+	rl.reload()
+	if got := config.Get().Addr; got != "127.0.0.1:8080" {
+		t.Errorf("addr after reload = %s, want the old one", got)
+	}
+	if policy, _ := janitor.settings(); policy.MaxAge != 2*time.Hour {
+		t.Errorf("retention.max_age after reload = %s", policy.MaxAge)
+	}
+	for token, want := range map[string]int{"tok-old": http.StatusUnauthorized, "tok-new": http.StatusOK} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Authorization", "Bearer "+token)
+		if got := serveAuth(auth, RoleUploader, r); got != want {
+			t.Errorf("%s after reload: status %d, want %d", token, got, want)
+		}
+	}
+
+	// Невалидный файл не меняет текущих настроек
+	writeConfig(t, path, "retention:\n  max_age: -1h\n")
+	rl.reload()
+	if policy, _ := janitor.settings(); policy.MaxAge != 2*time.Hour {
+		t.Errorf("retention.max_age after invalid reload = %s", policy.MaxAge)
+	}
+	if _, ok := auth.authorize("Bearer tok-new"); !ok {
+		t.Error("users dropped by invalid reload")
+	}
+}
+
+func TestRestartOnly(t *testing.T) {
+	cur := config.Default()
+	next := cur
+	next.LogLevel = "debug"
+	next.Retention.MaxAge = time.Hour
+	if got := restartOnly(cur, next); len(got) != 0 {
+		t.Errorf("safe changes reported as restart-only: %v", got)
+	}
+	next.Addr = "127.0.0.1:1"
+	next.GRPC.Addr = "127.0.0.1:2"
+	next.Server.H2C = !cur.Server.H2C
+	want := []string{"addr", "server", "grpc"}
+	if got := restartOnly(cur, next); !slices.Equal(got, want) {
+		t.Errorf("restartOnly = %v, want %v", got, want)
+	}
+}
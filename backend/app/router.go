@@ -2,17 +2,24 @@
 package app
 
 import (
+	"cmp"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
-	"github.com/google/uuid"
+	"gitlab.com/paradaise1/t1-hackaton-terraform/config"
 	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+	"gitlab.com/paradaise1/t1-hackaton-terraform/plugin"
 )
 
 func WriteJson(w http.ResponseWriter, data any) error {
@@ -20,14 +27,46 @@ func WriteJson(w http.ResponseWriter, data any) error {
 	return json.NewEncoder(w).Encode(data)
 }
 
-func NewRouter(repo log.Repo) http.Handler {
+// readFormFile читает поле file multipart-формы с лимитом
+// http.max_upload_bytes; при ошибке ответ уже записан.
+func readFormFile(w http.ResponseWriter, r *http.Request) ([]byte, string, bool) {
+	maxBytes := config.Get().HTTP.MaxUploadBytes
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+	err := r.ParseMultipartForm(maxBytes)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, fmt.Sprintf("upload exceeds %d bytes", maxBytes), http.StatusRequestEntityTooLarge)
+		return nil, "", false
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, "", false
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "file required", http.StatusBadRequest)
+		return nil, "", false
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return nil, "", false
+	}
+	return data, header.Filename, true
+}
+
+func NewRouter(repo log.Repo, auth *Auth, janitor *Janitor, snapshots *Snapshots, plugins *plugin.Manager, jobs *sync.WaitGroup) http.Handler {
 	r := chi.NewRouter()
 
 	// CORS middleware
 	r.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   []string{"http://localhost:5173", "http://127.0.0.1:5173"},
+		// Список источников читается на каждый запрос — меняется перезагрузкой конфига
+		AllowOriginFunc: func(r *http.Request, origin string) bool {
+			return slices.Contains(config.Get().HTTP.CORSOrigins, origin)
+		},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-Workspace"},
 		ExposedHeaders:   []string{"Link"},
 		AllowCredentials: true,
 		MaxAge:           300,
@@ -37,48 +76,162 @@ func NewRouter(repo log.Repo) http.Handler {
 	r.Use(middleware.RealIP)
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
+	r.Use(auth.Middleware)
+	r.Use(WorkspaceMiddleware(repo))
+
+	// Роли маршрутов: viewer — чтение, uploader — загрузка и разбор,
+	// admin — удаление и настройки
+	viewer := RequireRole(RoleViewer)
+	uploader := RequireRole(RoleUploader)
+	admin := RequireRole(RoleAdmin)
+
+	r.Post("/auth/login", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Name     string `json:"name"`
+			Password string `json:"password"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		u, ok := auth.Login(w, r, req.Name, req.Password)
+		if !ok {
+			http.Error(w, "invalid credentials", http.StatusUnauthorized)
+			return
+		}
+		WriteJson(w, u)
+	})
+
+	r.Post("/auth/logout", func(w http.ResponseWriter, r *http.Request) {
+		auth.Logout(w, r)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	r.With(viewer).Get("/auth/me", func(w http.ResponseWriter, r *http.Request) {
+		u, _ := UserFromContext(r.Context())
+		WriteJson(w, u)
+	})
 
-	r.Post("/upload", func(w http.ResponseWriter, r *http.Request) {
+	r.With(uploader).Post("/upload", func(w http.ResponseWriter, r *http.Request) {
+		jobs.Add(1)
+		defer jobs.Done()
 		ctx := r.Context()
-		err := r.ParseMultipartForm(10 << 20) // 10MB max
+		data, fileName, ok := readFormFile(w, r)
+		if !ok {
+			return
+		}
+		res, err := repo.UploadFile(ctx, data, log.UploadOptions{
+			FileName: fileName,
+			// workspace — прежнее имя поля, путалось с рабочим пространством
+			TFWorkspace: cmp.Or(r.FormValue("tf_workspace"), r.FormValue("workspace")),
+			RunID:       r.FormValue("run"),
+		})
+		if errors.Is(err, log.ErrQuotaExceeded) {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
 		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		WriteJson(w, res)
+	})
+
+	// План запуска (terraform show -json); run — run_id загрузок логов
+	r.With(uploader).Post("/plans", func(w http.ResponseWriter, r *http.Request) {
+		data, fileName, ok := readFormFile(w, r)
+		if !ok {
+			return
+		}
+		plan, err := repo.AddPlan(r.Context(), data, r.FormValue("run"), fileName)
+		if errors.Is(err, log.ErrInvalid) {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		file, _, err := r.FormFile("file")
 		if err != nil {
-			http.Error(w, "file required", http.StatusBadRequest)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		WriteJson(w, plan)
+	})
+
+	// Состояние terraform (terraform.tfstate или terraform show -json):
+	// ID ресурсов размечаются адресами в HTTP-транзакциях и диагностиках
+	r.With(uploader).Post("/state", func(w http.ResponseWriter, r *http.Request) {
+		data, fileName, ok := readFormFile(w, r)
+		if !ok {
+			return
+		}
+		state, err := repo.SetState(r.Context(), data, fileName)
+		if errors.Is(err, log.ErrInvalid) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		defer file.Close()
-		data, err := io.ReadAll(file)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
-		res, err := repo.UploadFile(ctx, data, uuid.NewString())
+		w.WriteHeader(http.StatusCreated)
+		WriteJson(w, state)
+	})
+
+	r.With(viewer).Get("/state", func(w http.ResponseWriter, r *http.Request) {
+		state, err := repo.GetState(r.Context())
+		if errors.Is(err, log.ErrNotFound) {
+			http.Error(w, "state not found", http.StatusNotFound)
+			return
+		}
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			http.Error(w, "failed to get state", http.StatusInternalServerError)
 			return
 		}
-		WriteJson(w, res)
+		WriteJson(w, state)
+	})
+
+	r.With(viewer).Get("/plans/{run}", func(w http.ResponseWriter, r *http.Request) {
+		report, err := repo.GetPlan(r.Context(), chi.URLParam(r, "run"))
+		if errors.Is(err, log.ErrNotFound) {
+			http.Error(w, "plan not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, "failed to get plan", http.StatusInternalServerError)
+			return
+		}
+		WriteJson(w, report)
 	})
 
-	r.Get("/logs", func(w http.ResponseWriter, r *http.Request) {
+	r.With(viewer).Get("/logs", func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 		q := r.URL.Query()
 		page, _ := strconv.Atoi(q.Get("page"))
 		limit, _ := strconv.Atoi(q.Get("limit"))
+		httpConf := config.Get().HTTP
+		if limit <= 0 {
+			limit = httpConf.DefaultPageSize
+		}
+		limit = min(limit, httpConf.MaxPageSize)
 		filters := log.ExportFilters{
 			TFResourceType: q.Get("tf_resource_type"),
 			TimestampFrom:  q.Get("timestamp_from"),
 			TimestampTo:    q.Get("timestamp_to"),
 			Level:          q.Get("level"),
 			Search:         q.Get("search"),
+			Query:          q.Get("q"),
+			TemplateID:     q.Get("template_id"),
+			TFReqID:        q.Get("tf_req_id"),
+			RunID:          q.Get("run_id"),
+			State:          q.Get("state"),
+			Assignee:       q.Get("assignee"),
 			Page:           page,
 			Limit:          limit,
 		}
 		logs, err := repo.GetLogs(ctx, filters)
+		if errors.Is(err, log.ErrInvalid) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -89,7 +242,7 @@ func NewRouter(repo log.Repo) http.Handler {
 		WriteJson(w, logs)
 	})
 
-	r.Get("/logs/{id}", func(w http.ResponseWriter, r *http.Request) {
+	r.With(viewer).Get("/logs/{id}", func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 		id := chi.URLParam(r, "id")
 		logEntry, err := repo.GetLogByID(ctx, id)
@@ -97,10 +250,42 @@ func NewRouter(repo log.Repo) http.Handler {
 			http.Error(w, "log not found", http.StatusNotFound)
 			return
 		}
-		WriteJson(w, logEntry)
+		// Тела HTTP с отступами; body_limit — байт на тело, 0 — целиком
+		limit := log.DefaultBodyPreview
+		if v, err := strconv.Atoi(r.URL.Query().Get("body_limit")); err == nil && v >= 0 {
+			limit = v
+		}
+		WriteJson(w, log.NewLogDetail(logEntry, limit))
+	})
+
+	r.With(viewer).Get("/logs/{id}/context", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		before, after := 10, 10
+		maxLines := config.Get().HTTP.MaxContextLines
+		if v, err := strconv.Atoi(q.Get("before")); err == nil && v >= 0 {
+			before = min(v, maxLines)
+		}
+		if v, err := strconv.Atoi(q.Get("after")); err == nil && v >= 0 {
+			after = min(v, maxLines)
+		}
+		var window time.Duration
+		if v := q.Get("window"); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil || d <= 0 {
+				http.Error(w, "invalid window", http.StatusBadRequest)
+				return
+			}
+			window = d
+		}
+		res, err := repo.GetLogContext(r.Context(), chi.URLParam(r, "id"), before, after, window)
+		if err != nil {
+			http.Error(w, "log not found", http.StatusNotFound)
+			return
+		}
+		WriteJson(w, res)
 	})
 
-	r.Post("/logs/mark-read", func(w http.ResponseWriter, r *http.Request) {
+	r.With(uploader).Post("/logs/mark-read", func(w http.ResponseWriter, r *http.Request) {
 		var req struct {
 			IDs []string `json:"ids"`
 		}
@@ -117,17 +302,93 @@ func NewRouter(repo log.Repo) http.Handler {
 		w.WriteHeader(http.StatusNoContent)
 	})
 
-	r.Get("/groups/{tf_req_id}", func(w http.ResponseWriter, r *http.Request) {
+	r.With(uploader).Post("/triage", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			IDs     []string           `json:"ids"`
+			Filters *log.ExportFilters `json:"filters"`
+			log.TriageUpdate
+		}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil || (len(req.IDs) == 0 && req.Filters == nil) ||
+			(req.State == nil && req.Assignee == nil) {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		if req.State != nil && !req.State.Valid() {
+			http.Error(w, "unknown state", http.StatusBadRequest)
+			return
+		}
+		updated, err := repo.UpdateTriage(r.Context(), req.IDs, req.Filters, req.TriageUpdate)
+		if errors.Is(err, log.ErrInvalid) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			http.Error(w, "failed to update triage state", http.StatusInternalServerError)
+			return
+		}
+		WriteJson(w, map[string]int{"updated": updated})
+	})
+
+	notesHandlers := func(kind log.NoteTargetKind, param string) (http.HandlerFunc, http.HandlerFunc) {
+		get := func(w http.ResponseWriter, r *http.Request) {
+			target := log.NoteTarget{Kind: kind, ID: chi.URLParam(r, param)}
+			notes, err := repo.GetNotes(r.Context(), target)
+			if err != nil {
+				http.Error(w, "not found", http.StatusNotFound)
+				return
+			}
+			WriteJson(w, notes)
+		}
+		add := func(w http.ResponseWriter, r *http.Request) {
+			var req struct {
+				Author string `json:"author"`
+				Text   string `json:"text"`
+			}
+			err := json.NewDecoder(r.Body).Decode(&req)
+			if err != nil || strings.TrimSpace(req.Text) == "" {
+				http.Error(w, "invalid request body", http.StatusBadRequest)
+				return
+			}
+			// С включённой аутентификацией автор — текущий пользователь
+			author := strings.TrimSpace(req.Author)
+			if u, ok := UserFromContext(r.Context()); ok && u.Name != "anonymous" {
+				author = u.Name
+			}
+			target := log.NoteTarget{Kind: kind, ID: chi.URLParam(r, param)}
+			note, err := repo.AddNote(r.Context(), target, author, req.Text)
+			if err != nil {
+				http.Error(w, "not found", http.StatusNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			WriteJson(w, note)
+		}
+		return get, add
+	}
+	getLogNotes, addLogNote := notesHandlers(log.NoteTargetLog, "id")
+	r.With(viewer).Get("/logs/{id}/notes", getLogNotes)
+	r.With(uploader).Post("/logs/{id}/notes", addLogNote)
+	getGroupNotes, addGroupNote := notesHandlers(log.NoteTargetGroup, "tf_req_id")
+	r.With(viewer).Get("/groups/{tf_req_id}/notes", getGroupNotes)
+	r.With(uploader).Post("/groups/{tf_req_id}/notes", addGroupNote)
+
+	r.With(viewer).Get("/groups/{tf_req_id}", func(w http.ResponseWriter, r *http.Request) {
 		tfReqID := chi.URLParam(r, "tf_req_id")
 		group, err := repo.GetGroupByReqID(r.Context(), tfReqID)
 		if err != nil {
 			http.Error(w, "not found", http.StatusNotFound)
 			return
 		}
-		WriteJson(w, group)
+		plan, err := repo.GetGroupPlan(r.Context(), tfReqID)
+		if err != nil {
+			http.Error(w, "failed to get plan", http.StatusInternalServerError)
+			return
+		}
+		WriteJson(w, log.Group{TFReqID: tfReqID, Items: group, Plan: plan})
 	})
 
-	r.Get("/timeline", func(w http.ResponseWriter, r *http.Request) {
+	r.With(viewer).Get("/timeline", func(w http.ResponseWriter, r *http.Request) {
 		timeline, err := repo.GetTimelineEntries(r.Context())
 		if err != nil {
 			http.Error(w, "failed to get timeline", http.StatusInternalServerError)
@@ -136,7 +397,7 @@ func NewRouter(repo log.Repo) http.Handler {
 		WriteJson(w, timeline)
 	})
 
-	r.Get("/metrics", func(w http.ResponseWriter, r *http.Request) {
+	r.With(viewer).Get("/metrics", func(w http.ResponseWriter, r *http.Request) {
 		metrics, err := repo.GetMetrics(r.Context())
 		if err != nil {
 			http.Error(w, "failed to get metrics", http.StatusInternalServerError)
@@ -145,16 +406,38 @@ func NewRouter(repo log.Repo) http.Handler {
 		WriteJson(w, metrics)
 	})
 
-	r.Post("/export/download", func(w http.ResponseWriter, r *http.Request) {
+	r.With(viewer).Get("/analytics/endpoints", func(w http.ResponseWriter, r *http.Request) {
+		stats, err := repo.GetEndpointAnalytics(r.Context(), r.URL.Query().Get("run"))
+		if err != nil {
+			http.Error(w, "failed to get endpoint analytics", http.StatusInternalServerError)
+			return
+		}
+		WriteJson(w, stats)
+	})
+
+	r.With(viewer).Post("/export/download", func(w http.ResponseWriter, r *http.Request) {
 		var req struct {
 			Filters log.ExportFilters `json:"filters"`
+			Format  string            `json:"format"` // json, har или формат плагина
 		}
 		err := json.NewDecoder(r.Body).Decode(&req)
 		if err != nil {
 			http.Error(w, "invalid request", http.StatusBadRequest)
 			return
 		}
+		if req.Format == "har" {
+			exportHAR(w, r, repo, req.Filters)
+			return
+		}
+		if req.Format != "" && req.Format != "json" {
+			exportPlugin(w, r, repo, plugins, req.Format, req.Filters)
+			return
+		}
 		data, err := repo.ExportLogs(r.Context(), req.Filters)
+		if errors.Is(err, log.ErrInvalid) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		if err != nil {
 			http.Error(w, "export failed", http.StatusInternalServerError)
 			return
@@ -164,7 +447,22 @@ func NewRouter(repo log.Repo) http.Handler {
 		w.Write(data)
 	})
 
-	r.Post("/export/telegram", func(w http.ResponseWriter, r *http.Request) {
+	r.With(viewer).Get("/export/formats", func(w http.ResponseWriter, r *http.Request) {
+		formats := append([]plugin.ExportFormat{{
+			Name:        "json",
+			ContentType: "application/json",
+			Extension:   "json",
+			Description: "JSON array of log entries",
+		}, {
+			Name:        "har",
+			ContentType: "application/json",
+			Extension:   "har",
+			Description: "HAR 1.2 of the HTTP transactions of matching entries, secrets redacted",
+		}}, plugins.Formats()...)
+		WriteJson(w, formats)
+	})
+
+	r.With(uploader).Post("/export/telegram", func(w http.ResponseWriter, r *http.Request) {
 		var req struct {
 			ChatID  string            `json:"chat_id"`
 			Filters log.ExportFilters `json:"filters"`
@@ -182,9 +480,224 @@ func NewRouter(repo log.Repo) http.Handler {
 		w.WriteHeader(http.StatusNoContent)
 	})
 
-	r.Get("/corrupted-logs", func(w http.ResponseWriter, r *http.Request) {
-		logs, _ := repo.GetCorruptedLogs(r.Context())
+	r.With(viewer).Get("/corrupted-logs", func(w http.ResponseWriter, r *http.Request) {
+		logs, err := repo.GetCorruptedLogs(r.Context(), r.URL.Query().Get("upload"))
+		if err != nil {
+			http.Error(w, "failed to get corrupted logs", http.StatusInternalServerError)
+			return
+		}
 		WriteJson(w, logs)
 	})
+
+	r.With(uploader).Post("/corrupted-logs/{id}/resolve", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Line string `json:"line"`
+		}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil || strings.TrimSpace(req.Line) == "" {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		entry, err := repo.ResolveCorruptedLog(r.Context(), chi.URLParam(r, "id"), req.Line)
+		switch {
+		case errors.Is(err, log.ErrInvalidLine):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case errors.Is(err, log.ErrNotFound):
+			http.Error(w, "corrupted line not found", http.StatusNotFound)
+			return
+		case err != nil:
+			http.Error(w, "failed to resolve", http.StatusInternalServerError)
+			return
+		}
+		WriteJson(w, entry)
+	})
+
+	r.With(viewer).Get("/diagnostics", func(w http.ResponseWriter, r *http.Request) {
+		groups, err := repo.GetDiagnostics(r.Context())
+		if err != nil {
+			http.Error(w, "failed to get diagnostics", http.StatusInternalServerError)
+			return
+		}
+		WriteJson(w, groups)
+	})
+
+	r.With(viewer).Get("/patterns", func(w http.ResponseWriter, r *http.Request) {
+		patterns, err := repo.GetPatterns(r.Context())
+		if err != nil {
+			http.Error(w, "failed to get patterns", http.StatusInternalServerError)
+			return
+		}
+		WriteJson(w, patterns)
+	})
+
+	r.With(viewer).Get("/uploads/{id}/novel", func(w http.ResponseWriter, r *http.Request) {
+		report, err := repo.GetNovelErrors(r.Context(), chi.URLParam(r, "id"))
+		if err != nil {
+			http.Error(w, "upload not found", http.StatusNotFound)
+			return
+		}
+		WriteJson(w, report)
+	})
+
+	// События terraform -json загрузки; trace — загрузка TF_LOG того же запуска
+	r.With(viewer).Get("/uploads/{id}/ui", func(w http.ResponseWriter, r *http.Request) {
+		report, err := repo.GetUIReport(r.Context(), chi.URLParam(r, "id"), r.URL.Query().Get("trace"))
+		if errors.Is(err, log.ErrNotFound) {
+			http.Error(w, "upload not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, "failed to build report", http.StatusInternalServerError)
+			return
+		}
+		WriteJson(w, report)
+	})
+
+	r.With(viewer).Get("/diff", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		base, target := q.Get("base"), q.Get("target")
+		if base == "" || target == "" {
+			http.Error(w, "base and target required", http.StatusBadRequest)
+			return
+		}
+		diff, err := repo.DiffUploads(r.Context(), base, target)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		WriteJson(w, diff)
+	})
+
+	r.With(viewer).Get("/uploads", func(w http.ResponseWriter, r *http.Request) {
+		uploads, err := repo.ListUploads(r.Context())
+		if err != nil {
+			http.Error(w, "failed to list uploads", http.StatusInternalServerError)
+			return
+		}
+		WriteJson(w, uploads)
+	})
+
+	// Закреплённые загрузки не вытесняются политикой хранения
+	pinUpload := func(pinned bool) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			err := repo.PinUpload(r.Context(), chi.URLParam(r, "id"), pinned)
+			if errors.Is(err, log.ErrNotFound) {
+				http.Error(w, "not found", http.StatusNotFound)
+				return
+			}
+			if err != nil {
+				http.Error(w, "failed to pin upload", http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+	r.With(uploader).Post("/uploads/{id}/pin", pinUpload(true))
+	r.With(uploader).Delete("/uploads/{id}/pin", pinUpload(false))
+	r.With(viewer).Post("/uploads/{id}/gate", gateHandler(repo))
+
+	r.With(admin).Get("/admin/usage", func(w http.ResponseWriter, r *http.Request) {
+		usage, err := repo.GetUsage(r.Context())
+		if err != nil {
+			http.Error(w, "failed to get usage", http.StatusInternalServerError)
+			return
+		}
+		WriteJson(w, struct {
+			log.Usage
+			Retention JanitorStatus `json:"retention"`
+		}{usage, janitor.Status()})
+	})
+
+	r.With(admin).Get("/admin/plugins", func(w http.ResponseWriter, r *http.Request) {
+		WriteJson(w, plugins.Status())
+	})
+
+	r.With(admin).Post("/admin/retention/run", func(w http.ResponseWriter, r *http.Request) {
+		report, err := janitor.Sweep(r.Context())
+		if err != nil {
+			http.Error(w, "failed to apply retention", http.StatusInternalServerError)
+			return
+		}
+		WriteJson(w, report)
+	})
+
+	r.With(admin).Post("/admin/snapshot", func(w http.ResponseWriter, r *http.Request) {
+		info, err := snapshots.Save(r.Context())
+		if errors.Is(err, ErrSnapshotsDisabled) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if err != nil {
+			http.Error(w, "failed to save snapshot", http.StatusInternalServerError)
+			return
+		}
+		WriteJson(w, info)
+	})
+
+	r.With(viewer).Get("/workspaces", func(w http.ResponseWriter, r *http.Request) {
+		all, err := repo.ListWorkspaces(r.Context())
+		if err != nil {
+			http.Error(w, "failed to list workspaces", http.StatusInternalServerError)
+			return
+		}
+		u, _ := UserFromContext(r.Context())
+		result := []log.Workspace{}
+		for _, ws := range all {
+			if u.CanAccess(ws.ID) {
+				result = append(result, ws)
+			}
+		}
+		WriteJson(w, result)
+	})
+
+	r.With(admin).Post("/workspaces", func(w http.ResponseWriter, r *http.Request) {
+		var req log.Workspace
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		ws, err := repo.CreateWorkspace(r.Context(), req)
+		switch {
+		case errors.Is(err, log.ErrInvalid):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case errors.Is(err, log.ErrConflict):
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		case err != nil:
+			http.Error(w, "failed to create workspace", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		WriteJson(w, ws)
+	})
+
+	r.With(viewer).Get("/workspaces/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		u, _ := UserFromContext(r.Context())
+		ws, err := repo.GetWorkspace(r.Context(), id)
+		if err != nil || !u.CanAccess(id) {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		WriteJson(w, ws)
+	})
+
+	r.With(admin).Delete("/workspaces/{id}", func(w http.ResponseWriter, r *http.Request) {
+		err := repo.DeleteWorkspace(r.Context(), chi.URLParam(r, "id"))
+		switch {
+		case errors.Is(err, log.ErrNotFound):
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		case errors.Is(err, log.ErrConflict):
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		case err != nil:
+			http.Error(w, "failed to delete workspace", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
 	return r
 }
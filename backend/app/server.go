@@ -0,0 +1,104 @@
+package app
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"time"
+
+	"gitlab.com/paradaise1/t1-hackaton-terraform/config"
+)
+
+func newServer(conf config.Config, handler http.Handler) (*http.Server, error) {
+	sc := conf.Server
+	server := &http.Server{
+		Addr:              conf.Addr,
+		Handler:           handler,
+		ReadHeaderTimeout: sc.ReadHeaderTimeout,
+		ReadTimeout:       sc.ReadTimeout,
+		WriteTimeout:      sc.WriteTimeout,
+		IdleTimeout:       sc.IdleTimeout,
+		Protocols:         new(http.Protocols),
+	}
+	// HTTP/2 включается поверх TLS автоматически, без TLS — только с h2c
+	server.Protocols.SetHTTP1(true)
+	server.Protocols.SetHTTP2(true)
+	server.Protocols.SetUnencryptedHTTP2(sc.H2C)
+
+	if sc.TLS.SelfSigned {
+		cert, err := selfSignedCert(conf.Addr)
+		if err != nil {
+			return nil, err
+		}
+		server.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+		slog.Warn("using self-signed TLS certificate, do not use in production")
+	}
+	return server, nil
+}
+
+func serve(server *http.Server, conf config.TLSConfig) error {
+	if conf.Enabled() {
+		// С self_signed сертификат уже в TLSConfig, файлы не нужны
+		return server.ListenAndServeTLS(conf.CertFile, conf.KeyFile)
+	}
+	return server.ListenAndServe()
+}
+
+// selfSignedCert выпускает сертификат для localhost и хоста из addr.
+func selfSignedCert(addr string) (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{Organization: []string{"tflogs dev"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(365 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+	if host, _, err := net.SplitHostPort(addr); err == nil && host != "" {
+		if ip := net.ParseIP(host); ip != nil {
+			if !ip.IsUnspecified() {
+				tmpl.IPAddresses = append(tmpl.IPAddresses, ip)
+			}
+		} else if host != "localhost" {
+			tmpl.DNSNames = append(tmpl.DNSNames, host)
+		}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
+
+// grpcTLSConfig — TLS для gRPC с тем же сертификатом, что и у HTTP-сервера;
+// nil, если TLS выключен.
+func grpcTLSConfig(conf config.TLSConfig, server *http.Server) (*tls.Config, error) {
+	if !conf.Enabled() {
+		return nil, nil
+	}
+	if server.TLSConfig != nil && len(server.TLSConfig.Certificates) > 0 {
+		return &tls.Config{Certificates: server.TLSConfig.Certificates}, nil
+	}
+	cert, err := tls.LoadX509KeyPair(conf.CertFile, conf.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{Certificates: []tls.Certificate{cert}}, nil
+}
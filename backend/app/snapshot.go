@@ -0,0 +1,144 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gitlab.com/paradaise1/t1-hackaton-terraform/config"
+	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+)
+
+const (
+	snapshotPrefix = "snapshot-"
+	snapshotSuffix = ".json.gz"
+	// Имена снимков сортируются лексикографически в порядке создания
+	snapshotTimeFormat = "20060102T150405.000000000Z"
+)
+
+var ErrSnapshotsDisabled = errors.New("snapshots disabled: snapshot.dir not configured")
+
+type SnapshotInfo struct {
+	File      string    `json:"file"`
+	Bytes     int64     `json:"bytes"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Snapshots сохраняет снимки репозитория в каталог и восстанавливает
+// последний из них.
+type Snapshots struct {
+	repo log.Repo
+	dir  string
+	keep int
+	mu   sync.Mutex // один снимок за раз
+}
+
+func NewSnapshots(repo log.Repo, conf config.SnapshotConfig) *Snapshots {
+	return &Snapshots{repo: repo, dir: conf.Dir, keep: max(1, conf.Keep)}
+}
+
+func (s *Snapshots) Enabled() bool {
+	return s.dir != ""
+}
+
+// Save пишет снимок во временный файл и атомарно переименовывает его,
+// затем удаляет снимки сверх keep.
+func (s *Snapshots) Save(ctx context.Context) (SnapshotInfo, error) {
+	if !s.Enabled() {
+		return SnapshotInfo{}, ErrSnapshotsDisabled
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return SnapshotInfo{}, err
+	}
+	tmp, err := os.CreateTemp(s.dir, ".snapshot-*")
+	if err != nil {
+		return SnapshotInfo{}, err
+	}
+	defer os.Remove(tmp.Name())
+	if err := s.repo.Snapshot(ctx, tmp); err != nil {
+		tmp.Close()
+		return SnapshotInfo{}, fmt.Errorf("write snapshot: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return SnapshotInfo{}, err
+	}
+	if err := tmp.Close(); err != nil {
+		return SnapshotInfo{}, err
+	}
+
+	now := time.Now().UTC()
+	name := filepath.Join(s.dir, snapshotPrefix+now.Format(snapshotTimeFormat)+snapshotSuffix)
+	if err := os.Rename(tmp.Name(), name); err != nil {
+		return SnapshotInfo{}, err
+	}
+	stat, err := os.Stat(name)
+	if err != nil {
+		return SnapshotInfo{}, err
+	}
+	s.prune()
+	return SnapshotInfo{File: name, Bytes: stat.Size(), CreatedAt: now}, nil
+}
+
+func (s *Snapshots) list() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasPrefix(e.Name(), snapshotPrefix) && strings.HasSuffix(e.Name(), snapshotSuffix) {
+			files = append(files, filepath.Join(s.dir, e.Name()))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func (s *Snapshots) prune() {
+	files, err := s.list()
+	if err != nil {
+		return
+	}
+	for len(files) > s.keep {
+		os.Remove(files[0])
+		files = files[1:]
+	}
+}
+
+// RestoreLatest восстанавливает репозиторий из последнего снимка и
+// возвращает имя файла; без снимков возвращает пустую строку.
+func (s *Snapshots) RestoreLatest(ctx context.Context) (string, error) {
+	if !s.Enabled() {
+		return "", nil
+	}
+	files, err := s.list()
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		return "", nil
+	}
+	latest := files[len(files)-1]
+	f, err := os.Open(latest)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if err := s.repo.Restore(ctx, f); err != nil {
+		return "", fmt.Errorf("restore %s: %w", latest, err)
+	}
+	return latest, nil
+}
@@ -0,0 +1,55 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+)
+
+// WorkspaceMiddleware выбирает рабочее пространство запроса по заголовку
+// X-Workspace или параметру ws и проверяет доступ пользователя к нему.
+// Запросы без пользователя пропускаются — их отклонит RequireRole.
+func WorkspaceMiddleware(repo log.Repo) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			u, ok := UserFromContext(r.Context())
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			id := r.Header.Get("X-Workspace")
+			if id == "" {
+				id = r.URL.Query().Get("ws")
+			}
+			ctx, err := selectWorkspace(r.Context(), repo, u, id)
+			if errors.Is(err, errWorkspaceForbidden) {
+				http.Error(w, "workspace forbidden", http.StatusForbidden)
+				return
+			}
+			if err != nil {
+				http.Error(w, "workspace not found", http.StatusNotFound)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+var errWorkspaceForbidden = errors.New("workspace forbidden")
+
+// selectWorkspace проверяет доступ пользователя к пространству id (пустой
+// id — пространство пользователя по умолчанию) и кладёт его в контекст.
+func selectWorkspace(ctx context.Context, repo log.Repo, u User, id string) (context.Context, error) {
+	if id == "" {
+		id = u.DefaultWorkspace()
+	}
+	if !u.CanAccess(id) {
+		return ctx, errWorkspaceForbidden
+	}
+	if _, err := repo.GetWorkspace(ctx, id); err != nil {
+		return ctx, err
+	}
+	return log.WithWorkspace(ctx, id), nil
+}
@@ -0,0 +1,105 @@
+// Плагин tflogs-plugin-projects — пример обработчика (см. пакет plugin).
+// Добавляет записям с HTTP-запросами метки:
+//
+//	project_id — идентификатор proj-... из URI
+//	project    — имя проекта из TFLOGS_PROJECTS (proj-abc=billing,proj-def=infra)
+//	service    — первый сегмент пути, например vpc или order-service
+//
+// и формат выгрузки markdown. Сборка: make plugins, затем plugins.dir: ./bin/plugins.
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+	"gitlab.com/paradaise1/t1-hackaton-terraform/plugin"
+)
+
+var projectRe = regexp.MustCompile(`/projects/(proj-[a-z0-9]+)`)
+
+type projects struct {
+	plugin.Base
+	names map[string]string
+}
+
+func (p *projects) Describe() plugin.Info {
+	return plugin.Info{
+		Name:             "projects",
+		Version:          "0.1.0",
+		OnEntry:          true,
+		OnUploadComplete: true,
+		ExportFormats: []plugin.ExportFormat{{
+			Name:        "markdown",
+			ContentType: "text/markdown; charset=utf-8",
+			Extension:   "md",
+			Description: "Markdown table of HTTP requests with project labels",
+		}},
+	}
+}
+
+func (p *projects) OnEntry(_ context.Context, l log.Log) (log.Log, error) {
+	uri := l.Tf_http_req_uri
+	if uri == "" {
+		return l, nil
+	}
+	if l.Labels == nil {
+		l.Labels = make(map[string]string)
+	}
+	if m := projectRe.FindStringSubmatch(uri); m != nil {
+		l.Labels["project_id"] = m[1]
+		if name, ok := p.names[m[1]]; ok {
+			l.Labels["project"] = name
+		}
+	}
+	if service, _, _ := strings.Cut(strings.TrimPrefix(uri, "/"), "/"); service != "" {
+		l.Labels["service"] = service
+	}
+	return l, nil
+}
+
+func (p *projects) OnUploadComplete(_ context.Context, ev log.UploadComplete) error {
+	logger.Info("upload complete", "workspace", ev.Workspace, "upload", ev.Upload.ID,
+		"errors", ev.Summary.Errors, "warnings", ev.Summary.Warnings)
+	return nil
+}
+
+func (p *projects) Export(_ context.Context, format string, logs []log.Log) ([]byte, error) {
+	if format != "markdown" {
+		return nil, plugin.ErrUnsupported
+	}
+	var b bytes.Buffer
+	b.WriteString("| time | method | uri | status | ms | project |\n|---|---|---|---|---|---|\n")
+	for _, tx := range log.HTTPTransactions(logs) {
+		project := ""
+		if tx.Request != nil {
+			project = cmp.Or(tx.Request.Labels["project"], tx.Request.Labels["project_id"])
+		}
+		status := ""
+		if tx.Status != 0 {
+			status = fmt.Sprint(tx.Status)
+		}
+		fmt.Fprintf(&b, "| %s | %s | `%s` | %s | %d | %s |\n",
+			tx.RequestAt.Format(time.RFC3339), tx.Method, strings.ReplaceAll(tx.URI, "|", `\|`),
+			status, tx.DurationMs, project)
+	}
+	return b.Bytes(), nil
+}
+
+var logger = plugin.Logger("projects")
+
+func main() {
+	names := make(map[string]string)
+	for pair := range strings.SplitSeq(os.Getenv("TFLOGS_PROJECTS"), ",") {
+		if id, name, ok := strings.Cut(strings.TrimSpace(pair), "="); ok {
+			names[id] = name
+		}
+	}
+	plugin.Serve(&projects{names: names})
+}
@@ -0,0 +1,336 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+)
+
+func runSummary(args []string) error {
+	var common commonFlags
+	fs := newFlagSet("summary", &common)
+	top := fs.Int("top", 10, "number of slowest RPCs to show")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	p, err := newPalette(common.color)
+	if err != nil {
+		return err
+	}
+	logs, err := loadInputs(fs.Args())
+	if err != nil {
+		return err
+	}
+	s := log.Summarize(logs, *top)
+	if common.json {
+		return writeJSON(os.Stdout, s)
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+	fmt.Fprintln(w, p.bold("Run"))
+	fmt.Fprintf(w, "  entries   %d\n", s.Entries)
+	if !s.Start.IsZero() {
+		fmt.Fprintf(w, "  time      %s .. %s (%s)\n", s.Start.Format(time.DateTime), s.End.Format(time.TimeOnly), formatMs(s.DurationMs))
+	}
+	keys := slices.Sorted(maps.Keys(s.Config))
+	width := 9
+	for _, k := range keys {
+		width = max(width, len(k))
+	}
+	for _, k := range keys {
+		fmt.Fprintf(w, "  %-*s %s\n", width, k, s.Config[k])
+	}
+
+	fmt.Fprintln(w, p.bold("\nProblems"))
+	fmt.Fprintf(w, "  errors    %s\n", p.severity(cond(s.Errors > 0, "error", ""), fmt.Sprint(s.Errors)))
+	fmt.Fprintf(w, "  warnings  %s\n", p.severity(cond(s.Warnings > 0, "warning", ""), fmt.Sprint(s.Warnings)))
+	levels := slices.Sorted(maps.Keys(s.Levels))
+	parts := make([]string, 0, len(levels))
+	for _, l := range levels {
+		parts = append(parts, fmt.Sprintf("%s=%d", l, s.Levels[l]))
+	}
+	fmt.Fprintf(w, "  levels    %s\n", strings.Join(parts, " "))
+
+	if len(s.Resources) > 0 {
+		fmt.Fprintln(w, p.bold("\nResources"))
+		for _, addr := range slices.Sorted(maps.Keys(s.Resources)) {
+			outcome := s.Resources[addr]
+			fmt.Fprintf(w, "  %-8s %s\n", p.severity(outcome, outcome), addr)
+		}
+	}
+
+	fmt.Fprintln(w, p.bold("\nHTTP"))
+	fmt.Fprintf(w, "  requests  %d\n", s.HTTPRequests)
+	codes := slices.Sorted(maps.Keys(s.HTTPStatuses))
+	parts = parts[:0]
+	for _, c := range codes {
+		parts = append(parts, fmt.Sprintf("%s×%d", p.status(c), s.HTTPStatuses[c]))
+	}
+	if len(parts) > 0 {
+		fmt.Fprintf(w, "  statuses  %s\n", strings.Join(parts, " "))
+	}
+
+	if len(s.SlowestRPCs) > 0 {
+		fmt.Fprintf(w, "%s\n", p.bold(fmt.Sprintf("\nSlowest RPCs (%d total)", s.RPCs)))
+		for _, c := range s.SlowestRPCs {
+			fmt.Fprintf(w, "  %8s  %-26s %s %s\n", formatMs(c.DurationMs), c.RPC,
+				p.severity(c.Severity, c.Resource), p.dim(c.TFReqID))
+		}
+	}
+	return nil
+}
+
+func cond(ok bool, a, b string) string {
+	if ok {
+		return a
+	}
+	return b
+}
+
+func runGrep(args []string) error {
+	var common commonFlags
+	fs := newFlagSet("grep", &common)
+	count := fs.Bool("c", false, "print only the number of matching entries")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("%w: query required", errUsage)
+	}
+	q, err := log.ParseQuery(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	p, err := newPalette(common.color)
+	if err != nil {
+		return err
+	}
+	logs, err := loadInputs(fs.Args()[1:])
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+	enc := json.NewEncoder(w)
+	matched := 0
+	for _, l := range logs {
+		if !q.Match(&l) {
+			continue
+		}
+		matched++
+		switch {
+		case *count:
+		case common.json:
+			if err := enc.Encode(l); err != nil {
+				return err
+			}
+		default:
+			fmt.Fprintln(w, entryLine(p, l))
+		}
+	}
+	if *count {
+		fmt.Fprintln(w, matched)
+	}
+	return nil
+}
+
+func runErrors(args []string) error {
+	var common commonFlags
+	fs := newFlagSet("errors", &common)
+	warnings := fs.Bool("warnings", false, "include warnings")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	p, err := newPalette(common.color)
+	if err != nil {
+		return err
+	}
+	logs, err := loadInputs(fs.Args())
+	if err != nil {
+		return err
+	}
+	reports := log.ErrorReports(logs, *warnings)
+	if common.json {
+		return writeJSON(os.Stdout, reports)
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+	if len(reports) == 0 {
+		fmt.Fprintln(w, p.green("no errors"))
+		return nil
+	}
+	for i, r := range reports {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		title := cond(r.Summary != "", r.Summary, firstLine(r.Message))
+		fmt.Fprintf(w, "%s %s", p.severity(r.Severity, strings.ToUpper(r.Severity)), p.bold(title))
+		if r.Count > 1 {
+			fmt.Fprintf(w, " %s", p.dim(fmt.Sprintf("(×%d)", r.Count)))
+		}
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "  %s %s\n", p.dim("at"), r.Timestamp)
+		if r.Resource != "" {
+			fmt.Fprintf(w, "  %s %s\n", p.dim("resource"), r.Resource)
+		}
+		if r.RPC != "" {
+			fmt.Fprintf(w, "  %s %s %s\n", p.dim("rpc"), r.RPC, p.dim(r.TFReqID))
+		}
+		if d := r.Diag; d != nil {
+			if d.ErrorCode != "" {
+				fmt.Fprintf(w, "  %s %s\n", p.dim("code"), d.ErrorCode)
+			}
+			if d.HTTPStatus != 0 {
+				fmt.Fprintf(w, "  %s %s %s\n", p.dim("status"), p.status(d.HTTPStatus), d.HTTPStatusText)
+			}
+			if d.RequestID != "" {
+				fmt.Fprintf(w, "  %s %s\n", p.dim("request id"), d.RequestID)
+			}
+			if d.AttributePath != "" {
+				fmt.Fprintf(w, "  %s %s\n", p.dim("attribute"), d.AttributePath)
+			}
+		}
+		if r.Detail != "" {
+			for line := range strings.SplitSeq(r.Detail, "\n") {
+				fmt.Fprintf(w, "  │ %s\n", line)
+			}
+		}
+		for _, t := range r.HTTP {
+			fmt.Fprintf(w, "  %s %-6s %s %s %s\n", p.dim("http"), t.Method, t.URI, p.status(t.Status), p.dim(formatMs(t.DurationMs)))
+		}
+	}
+	return nil
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+var defaultExportFields = []string{
+	"@timestamp", "@level", "@module", "tf_req_id", "tf_rpc", "tf_resource_type",
+	"tf_http_req_method", "tf_http_req_uri", "tf_http_res_status_code", "@message",
+}
+
+func runExport(args []string) error {
+	var common commonFlags
+	fs := newFlagSet("export", &common)
+	format := fs.String("format", "ndjson", "output format: csv or ndjson")
+	query := fs.String("query", "", "export only entries matching the query")
+	fields := fs.String("fields", strings.Join(defaultExportFields, ","), "csv columns")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *format != "csv" && *format != "ndjson" {
+		return fmt.Errorf("%w: --format must be csv or ndjson", errUsage)
+	}
+	q, err := log.ParseQuery(*query)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	columns := strings.Split(*fields, ",")
+	getters := make([]func(l *log.Log) string, len(columns))
+	for i, c := range columns {
+		c = strings.TrimSpace(c)
+		columns[i] = c
+		// Значение колонки — через язык запросов: те же имена полей
+		get, err := log.FieldValue(c)
+		if err != nil {
+			return fmt.Errorf("%w: --fields: %v", errUsage, err)
+		}
+		getters[i] = get
+	}
+	logs, err := loadInputs(fs.Args())
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+	if *format == "ndjson" {
+		enc := json.NewEncoder(w)
+		for _, l := range logs {
+			if q.Match(&l) {
+				if err := enc.Encode(l); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Write(columns)
+	row := make([]string, len(columns))
+	for _, l := range logs {
+		if !q.Match(&l) {
+			continue
+		}
+		for i, get := range getters {
+			row[i] = get(&l)
+		}
+		cw.Write(row)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func runTimeline(args []string) error {
+	var common commonFlags
+	fs := newFlagSet("timeline", &common)
+	width := fs.Int("width", 40, "width of the time bar in characters")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	p, err := newPalette(common.color)
+	if err != nil {
+		return err
+	}
+	logs, err := loadInputs(fs.Args())
+	if err != nil {
+		return err
+	}
+	calls := log.RPCCalls(logs)
+	calls = slices.DeleteFunc(calls, func(c log.RPCCall) bool { return c.Start.IsZero() })
+	if common.json {
+		return writeJSON(os.Stdout, calls)
+	}
+	if len(calls) == 0 {
+		return nil
+	}
+
+	start, end := calls[0].Start, calls[0].End
+	for _, c := range calls {
+		if c.End.After(end) {
+			end = c.End
+		}
+	}
+	span := max(end.Sub(start), time.Millisecond)
+	scale := func(t time.Time) int {
+		return int(float64(t.Sub(start)) / float64(span) * float64(*width-1))
+	}
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+	fmt.Fprintf(w, "%s  %s .. %s (%s)\n", p.bold("timeline"), start.Format(time.TimeOnly), end.Format(time.TimeOnly), formatMs(int(span.Milliseconds())))
+	sort.SliceStable(calls, func(i, j int) bool { return calls[i].Start.Before(calls[j].Start) })
+	for _, c := range calls {
+		from, to := scale(c.Start), scale(c.End)
+		bar := strings.Repeat(" ", from) + strings.Repeat("█", max(1, to-from+1))
+		bar += strings.Repeat(" ", max(0, *width-len([]rune(bar))))
+		fmt.Fprintf(w, "%s │%s│ %7s %-26s %s\n", p.dim(c.Start.Format("15:04:05.000")),
+			p.severity(cond(c.Severity == "", "ok", c.Severity), bar), formatMs(c.DurationMs), c.RPC, c.Resource)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const grepInput = `{"@level":"debug","@message":"Sending HTTP Request","tf_rpc":"ReadResource","tf_http_op_type":"request","tf_http_req_method":"GET"}
+{"@level":"debug","@message":"Received HTTP Response","tf_rpc":"ReadResource","tf_http_op_type":"response","tf_http_res_status_code":200}
+{"@level":"debug","@message":"Received HTTP Response","tf_rpc":"ApplyResourceChange","tf_http_op_type":"response","tf_http_res_status_code":502}
+{"@level":"error","@message":"Error: quota exceeded","tf_rpc":"ApplyResourceChange"}
+`
+
+// captureStdout выполняет fn, перехватив os.Stdout.
+func captureStdout(t *testing.T, fn func() error) (string, error) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+	runErr := fn()
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out), runErr
+}
+
+func TestGrepCount(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "apply.json")
+	if err := os.WriteFile(path, []byte(grepInput), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"tf_rpc = ApplyResourceChange", "2"},
+		{"tf_http_res_status_code >= 500 OR severity = error", "2"},
+		{"tf_http_res_status_code < 500", "1"},
+		{"NOT tf_http_op_type exists", "1"},
+		{`"quota exceeded"`, "1"},
+	}
+	for _, tt := range tests {
+		out, err := captureStdout(t, func() error { return runGrep([]string{"-c", tt.query, path}) })
+		if err != nil {
+			t.Fatalf("grep %q: %v", tt.query, err)
+		}
+		if got := strings.TrimSpace(out); got != tt.want {
+			t.Errorf("grep -c %q = %s, want %s", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestGrepInvalidQuery(t *testing.T) {
+	err := runGrep([]string{"tf_rpc =", "-"})
+	if !errors.Is(err, errUsage) || !strings.Contains(err.Error(), "position 9") {
+		t.Fatalf("grep with invalid query: %v, want usage error with position", err)
+	}
+}
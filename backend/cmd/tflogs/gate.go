@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+)
+
+// errGateFailed — запуск не прошёл проверку; отдельный код выхода, чтобы
+// в CI отличать его от ошибок самой утилиты (allow_failure: exit_codes).
+var errGateFailed = errors.New("gate failed")
+
+const exitGateFailed = 3
+
+func runGate(args []string) error {
+	var common commonFlags
+	fs := newFlagSet("gate", &common)
+	def := log.DefaultGateThresholds()
+	var t log.GateThresholds
+	fs.IntVar(&t.MaxErrors, "max-errors", def.MaxErrors, "allowed error diagnostics, -1 disables")
+	fs.IntVar(&t.MaxHTTP5xx, "max-5xx", def.MaxHTTP5xx, "allowed HTTP 5xx responses, -1 disables")
+	fs.IntVar(&t.MaxRetries, "max-retries", def.MaxRetries, "allowed retries, -1 disables")
+	fs.DurationVar(&t.MaxRPCDuration, "max-rpc-duration", 0, "limit for a single provider RPC, 0 disables")
+	fs.DurationVar(&t.MaxHTTPDuration, "max-http-duration", 0, "limit for a single HTTP request, 0 disables")
+	junit := fs.String("junit", "", "write JUnit XML report to `file` (- for stdout)")
+	name := fs.String("name", "", "test suite name (default: first file name)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	p, err := newPalette(common.color)
+	if err != nil {
+		return err
+	}
+	logs, err := loadInputs(fs.Args())
+	if err != nil {
+		return err
+	}
+	if *name == "" {
+		*name = "stdin"
+		if fs.NArg() > 0 && fs.Arg(0) != "-" {
+			*name = filepath.Base(fs.Arg(0))
+		}
+	}
+	report := log.EvaluateGate(*name, logs, t)
+
+	if *junit != "" {
+		out, err := report.JUnit()
+		if err != nil {
+			return err
+		}
+		if *junit == "-" {
+			os.Stdout.Write(out)
+		} else if err := os.WriteFile(*junit, out, 0o644); err != nil {
+			return err
+		}
+	}
+	switch {
+	case *junit == "-":
+	case common.json:
+		if err := writeJSON(os.Stdout, report); err != nil {
+			return err
+		}
+	default:
+		printGate(p, report)
+	}
+	if !report.Passed {
+		return errGateFailed
+	}
+	return nil
+}
+
+func printGate(p palette, r log.GateReport) {
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+	failed := 0
+	for _, c := range r.Cases {
+		if len(c.Failures) == 0 {
+			fmt.Fprintf(w, "%s %s %s\n", p.green("PASS"), c.Name, p.dim(formatMs(c.DurationMs)))
+			continue
+		}
+		failed++
+		fmt.Fprintf(w, "%s %s %s\n", p.red("FAIL"), p.bold(c.Name), p.dim(formatMs(c.DurationMs)))
+		for _, f := range c.Failures {
+			fmt.Fprintf(w, "  %s %s\n", p.yellow("["+f.Kind+"]"), f.Message)
+			for line := range strings.SplitSeq(f.Detail, "\n") {
+				if line != "" {
+					fmt.Fprintf(w, "    │ %s\n", line)
+				}
+			}
+		}
+	}
+	c := r.Counts
+	fmt.Fprintf(w, "\nerrors=%d http_5xx=%d retries=%d slow_rpcs=%d slow_http=%d\n",
+		c.Errors, c.HTTP5xx, c.Retries, c.SlowRPC, c.SlowHTTP)
+	if r.Passed {
+		fmt.Fprintf(w, "%s %d resources\n", p.green("gate passed:"), len(r.Cases))
+	} else {
+		fmt.Fprintf(w, "%s %d of %d cases failed\n", p.red("gate failed:"), failed, len(r.Cases))
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+)
+
+// commonFlags — флаги, общие для всех команд.
+type commonFlags struct {
+	json  bool
+	color string
+}
+
+func newFlagSet(name string, common *commonFlags) *flag.FlagSet {
+	fs := flag.NewFlagSet("tflogs "+name, flag.ContinueOnError)
+	fs.BoolVar(&common.json, "json", false, "machine-readable JSON output")
+	fs.StringVar(&common.color, "color", "auto", "colorize output: auto, always, never")
+	return fs
+}
+
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	return nil
+}
+
+// loadInputs читает записи из файлов или stdin, размечает шаблоны сообщений
+// (нужны для отпечатков ошибок) и сообщает о битых строках в stderr.
+func loadInputs(paths []string) ([]log.Log, error) {
+	if len(paths) == 0 {
+		if stat, err := os.Stdin.Stat(); err == nil && stat.Mode()&os.ModeCharDevice != 0 {
+			return nil, fmt.Errorf("%w: no input files and stdin is a terminal", errUsage)
+		}
+		paths = []string{"-"}
+	}
+	miner := log.NewTemplateMiner()
+	var all []log.Log
+	for _, path := range paths {
+		logs, corrupted, err := loadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if len(corrupted) > 0 {
+			repaired := 0
+			for _, c := range corrupted {
+				if c.RepairOK {
+					repaired++
+				}
+			}
+			fmt.Fprintf(os.Stderr, "%s: %d corrupted lines (%d repaired)\n", displayName(path), len(corrupted), repaired)
+		}
+		for i := range logs {
+			logs[i].TemplateID = miner.Add(logs[i].At_message)
+		}
+		all = append(all, logs...)
+	}
+	return all, nil
+}
+
+func displayName(path string) string {
+	if path == "-" {
+		return "stdin"
+	}
+	return path
+}
+
+func loadFile(path string) ([]log.Log, []log.CorruptedLine, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer zr.Close()
+		return log.LoadLogs(zr)
+	}
+	return log.LoadLogs(br)
+}
@@ -0,0 +1,77 @@
+// Команда tflogs анализирует файлы TF_LOG без сервера:
+//
+//	tflogs summary apply.json
+//	terraform apply 2>&1 | tflogs errors
+//	tflogs grep 'severity = error OR tf_http_res_status_code >= 500' *.json
+//	tflogs export --format=csv --query 'tf_rpc = ApplyResourceChange' run.json > run.csv
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(args []string) error
+}
+
+var commands = []command{
+	{"summary", "[flags] [file...]", "run info, error counts and slowest RPCs", runSummary},
+	{"grep", "[flags] QUERY [file...]", "print entries matching a query", runGrep},
+	{"errors", "[flags] [file...]", "error diagnostics with their HTTP context", runErrors},
+	{"export", "[flags] [file...]", "export entries as csv or ndjson", runExport},
+	{"timeline", "[flags] [file...]", "RPC calls on a time axis", runTimeline},
+	{"gate", "[flags] [file...]", "CI check against thresholds with JUnit XML report", runGate},
+}
+
+// errUsage — ошибка в аргументах; печатается вместе с подсказкой.
+var errUsage = errors.New("usage")
+
+// Коды выхода: 0 — успех, 1 — ошибка выполнения, 2 — неверные аргументы,
+// 3 — запуск не прошёл gate.
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	name := os.Args[1]
+	if name == "-h" || name == "--help" || name == "help" {
+		usage()
+		return
+	}
+	for _, c := range commands {
+		if c.name != name {
+			continue
+		}
+		err := c.run(os.Args[2:])
+		switch {
+		case err == nil, errors.Is(err, flag.ErrHelp):
+			return
+		case errors.Is(err, errGateFailed):
+			os.Exit(exitGateFailed)
+		case errors.Is(err, errUsage):
+			fmt.Fprintf(os.Stderr, "tflogs %s: %v\nusage: tflogs %s %s\n", c.name, err, c.name, c.usage)
+			os.Exit(2)
+		default:
+			fmt.Fprintf(os.Stderr, "tflogs %s: %v\n", c.name, err)
+			os.Exit(1)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "tflogs: unknown command %q\n", name)
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: tflogs <command> [flags] [file...]")
+	fmt.Fprintln(os.Stderr, "\nFiles may be gzip-compressed; without files or with \"-\" stdin is read.\n\ncommands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(os.Stderr, "\nrun \"tflogs <command> -h\" for command flags")
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+)
+
+// palette раскрашивает вывод ANSI-кодами; без цвета возвращает текст как есть.
+type palette struct {
+	enabled bool
+}
+
+func newPalette(mode string) (palette, error) {
+	switch mode {
+	case "always":
+		return palette{true}, nil
+	case "never":
+		return palette{false}, nil
+	case "auto":
+		if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+			return palette{false}, nil
+		}
+		stat, err := os.Stdout.Stat()
+		return palette{err == nil && stat.Mode()&os.ModeCharDevice != 0}, nil
+	}
+	return palette{}, fmt.Errorf("%w: --color must be auto, always or never", errUsage)
+}
+
+func (p palette) wrap(code, s string) string {
+	if !p.enabled || s == "" {
+		return s
+	}
+	return "\x1b[" + code + "m" + s + "\x1b[0m"
+}
+
+func (p palette) bold(s string) string   { return p.wrap("1", s) }
+func (p palette) dim(s string) string    { return p.wrap("2", s) }
+func (p palette) red(s string) string    { return p.wrap("31", s) }
+func (p palette) yellow(s string) string { return p.wrap("33", s) }
+func (p palette) green(s string) string  { return p.wrap("32", s) }
+func (p palette) cyan(s string) string   { return p.wrap("36", s) }
+
+// severity раскрашивает текст по серьёзности: error, warning или прочее.
+func (p palette) severity(sev, s string) string {
+	switch sev {
+	case "error":
+		return p.red(s)
+	case "warning":
+		return p.yellow(s)
+	}
+	return s
+}
+
+func (p palette) level(level string) string {
+	s := fmt.Sprintf("%-5s", strings.ToUpper(level))
+	switch strings.ToLower(level) {
+	case "error":
+		return p.red(s)
+	case "warn", "warning":
+		return p.yellow(s)
+	case "info":
+		return p.green(s)
+	}
+	return p.dim(s)
+}
+
+func (p palette) status(code int) string {
+	s := fmt.Sprint(code)
+	switch {
+	case code >= 500:
+		return p.red(s)
+	case code >= 400:
+		return p.yellow(s)
+	case code == 0:
+		return p.dim("-")
+	}
+	return p.green(s)
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// entryLine — однострочное представление записи для терминала.
+func entryLine(p palette, l log.Log) string {
+	var b strings.Builder
+	b.WriteString(p.dim(l.At_timestamp))
+	b.WriteString(" ")
+	b.WriteString(p.level(l.At_level))
+	if l.At_module != "" {
+		b.WriteString(" " + p.cyan(l.At_module))
+	}
+	if l.Tf_rpc != "" {
+		b.WriteString(" " + p.dim("["+l.Tf_rpc+"]"))
+	}
+	msg, _, _ := strings.Cut(l.At_message, "\n")
+	b.WriteString(" " + p.severity(log.Severity(l), msg))
+	if l.Tf_http_op_type == "request" {
+		b.WriteString(" " + l.Tf_http_req_method + " " + l.Tf_http_req_uri)
+	}
+	if l.Tf_http_res_status_code != 0 {
+		b.WriteString(" " + p.status(l.Tf_http_res_status_code))
+	}
+	if l.Diagnostic_summary != "" {
+		b.WriteString(": " + p.severity(log.Severity(l), l.Diagnostic_summary))
+	}
+	return b.String()
+}
+
+func formatMs(ms int) string {
+	if ms < 1000 {
+		return fmt.Sprintf("%dms", ms)
+	}
+	return fmt.Sprintf("%.1fs", float64(ms)/1000)
+}
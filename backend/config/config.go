@@ -1,37 +1,242 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
 	"os"
+	"sync/atomic"
+	"time"
 
 	"github.com/goccy/go-yaml"
 )
 
-var config Config
+// DefaultPath — файл конфигурации, если не задан --config или TFLOGS_CONFIG.
+const DefaultPath = "appconfig.yml"
+
+var current atomic.Pointer[Config]
 
 type Config struct {
+	Addr      string          `yaml:"addr"`
+	LogLevel  string          `yaml:"log_level"` // debug, info, warn, error
+	Server    ServerConfig    `yaml:"server"`
+	GRPC      GRPCConfig      `yaml:"grpc"`
+	HTTP      HTTPConfig      `yaml:"http"`
+	Auth      AuthConfig      `yaml:"auth"`
+	Retention RetentionConfig `yaml:"retention"`
+	Snapshot  SnapshotConfig  `yaml:"snapshot"`
+	Gate      GateConfig      `yaml:"gate"`
+	Plugins   PluginsConfig   `yaml:"plugins"`
+}
+
+// ServerConfig — параметры HTTP-сервера; меняются только перезапуском.
+type ServerConfig struct {
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	ReadTimeout       time.Duration `yaml:"read_timeout"` // 0 — без ограничения
+	WriteTimeout      time.Duration `yaml:"write_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout"`
+	// Сколько ждать завершения текущих запросов и загрузок при остановке
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	H2C             bool          `yaml:"h2c"` // HTTP/2 без TLS, например за прокси
+	TLS             TLSConfig     `yaml:"tls"`
+}
+
+// TLSConfig включает HTTPS (и HTTP/2): файлы сертификата или
+// самоподписанный сертификат для разработки.
+type TLSConfig struct {
+	CertFile   string `yaml:"cert_file"`
+	KeyFile    string `yaml:"key_file"`
+	SelfSigned bool   `yaml:"self_signed"`
+}
+
+func (t TLSConfig) Enabled() bool {
+	return t.CertFile != "" || t.SelfSigned
+}
+
+// GRPCConfig — gRPC API (proto/tflogs/v1) на отдельном адресе; пустой
+// Addr отключает его. TLS берётся из server.tls.
+type GRPCConfig struct {
 	Addr string `yaml:"addr"`
 }
 
-func getDefaultConfig() Config {
+type HTTPConfig struct {
+	CORSOrigins     []string `yaml:"cors_origins"`
+	MaxUploadBytes  int64    `yaml:"max_upload_bytes"`
+	DefaultPageSize int      `yaml:"default_page_size"`
+	MaxPageSize     int      `yaml:"max_page_size"`
+	MaxContextLines int      `yaml:"max_context_lines"` // предел before/after в /logs/{id}/context
+}
+
+// AuthConfig — пользователи и их токены. Без пользователей сервер не
+// запускается, если аутентификация не отключена явно через Disabled.
+type AuthConfig struct {
+	// Disabled — все запросы, включая gRPC, выполняются анонимно с правами
+	// admin; только для локальной разработки
+	Disabled   bool          `yaml:"disabled"`
+	Secret     string        `yaml:"secret"` // ключ подписи cookie сессий
+	SessionTTL time.Duration `yaml:"session_ttl"`
+	Users      []UserConfig  `yaml:"users"`
+}
+
+type UserConfig struct {
+	Name         string   `yaml:"name"`
+	PasswordHash string   `yaml:"password_hash"` // bcrypt
+	Role         string   `yaml:"role"`          // viewer, uploader, admin
+	Tokens       []string `yaml:"tokens"`
+	// Рабочие пространства, доступные пользователю и его токенам; пусто —
+	// только default, "*" — все. Admin имеет доступ ко всем.
+	Workspaces []string `yaml:"workspaces"`
+}
+
+// RetentionConfig — ограничения хранилища в памяти; нулевые значения
+// отключают соответствующую проверку.
+type RetentionConfig struct {
+	MaxAge         time.Duration `yaml:"max_age"`
+	MaxEntries     int           `yaml:"max_entries"`
+	MaxMemoryBytes int64         `yaml:"max_memory_bytes"`
+	Evict          string        `yaml:"evict"`    // oldest, lru
+	Interval       time.Duration `yaml:"interval"` // период запуска очистки
+}
+
+// SnapshotConfig — снимки состояния в памяти. Пустой Dir отключает снимки.
+type SnapshotConfig struct {
+	Dir  string `yaml:"dir"`
+	Keep int    `yaml:"keep"` // сколько последних снимков хранить
+}
+
+// GateConfig — пороги CI-проверки по умолчанию (POST /uploads/{id}/gate).
+// Счётчик -1 отключает проверку, длительность 0 — тоже.
+type GateConfig struct {
+	MaxErrors       int           `yaml:"max_errors"`
+	MaxHTTP5xx      int           `yaml:"max_http_5xx"`
+	MaxRetries      int           `yaml:"max_retries"`
+	MaxRPCDuration  time.Duration `yaml:"max_rpc_duration"`
+	MaxHTTPDuration time.Duration `yaml:"max_http_duration"`
+}
+
+// PluginsConfig — плагины-обработчики (каталог исполняемых файлов, см.
+// пакет plugin). Пустой Dir отключает плагины.
+type PluginsConfig struct {
+	Dir     string        `yaml:"dir"`
+	Timeout time.Duration `yaml:"timeout"` // предел запуска плагина и одного вызова
+}
+
+func Default() Config {
 	return Config{
-		Addr: "0.0.0.0:80",
+		Addr:     "0.0.0.0:80",
+		LogLevel: "info",
+		Server: ServerConfig{
+			ReadHeaderTimeout: 10 * time.Second,
+			ReadTimeout:       5 * time.Minute, // загрузка больших логов
+			WriteTimeout:      5 * time.Minute, // выгрузка экспорта
+			IdleTimeout:       2 * time.Minute,
+			ShutdownTimeout:   30 * time.Second,
+		},
+		HTTP: HTTPConfig{
+			CORSOrigins:     []string{"http://localhost:5173", "http://127.0.0.1:5173"},
+			MaxUploadBytes:  10 << 20,
+			DefaultPageSize: 50,
+			MaxPageSize:     1000,
+			MaxContextLines: 500,
+		},
+		Auth: AuthConfig{
+			SessionTTL: 12 * time.Hour,
+		},
+		Retention: RetentionConfig{
+			Evict:    "oldest",
+			Interval: time.Minute,
+		},
+		Snapshot: SnapshotConfig{
+			Keep: 3,
+		},
+		Gate: GateConfig{
+			MaxRetries: -1,
+		},
+		Plugins: PluginsConfig{
+			Timeout: 5 * time.Second,
+		},
 	}
 }
 
-func ReadConfig(path string) (Config, error) {
-	conf := getDefaultConfig()
-	config = conf
-	file, err := os.Open(path)
-	if err != nil {
+type LoadOptions struct {
+	Path string
+	// Required — отсутствие файла считается ошибкой (путь задан явно).
+	Required bool
+	// Overrides — значения из флагов командной строки, ключ вида retention.max_age.
+	Overrides map[string]string
+}
+
+// Load собирает конфигурацию слоями: значения по умолчанию, файл,
+// переменные окружения TFLOGS_*, флаги — и проверяет результат.
+func Load(opts LoadOptions) (Config, error) {
+	conf := Default()
+	if opts.Path == "" {
+		opts.Path = DefaultPath
+	}
+	if err := readFile(opts.Path, &conf); err != nil {
+		if !errors.Is(err, fs.ErrNotExist) || opts.Required {
+			return conf, fmt.Errorf("config %s: %w", opts.Path, err)
+		}
+		slog.Warn("config file not found, using defaults and environment", "path", opts.Path)
+	}
+	if err := applyEnv(&conf, os.LookupEnv); err != nil {
 		return conf, err
 	}
-	if err = yaml.NewDecoder(file).Decode(&conf); err != nil {
+	for key, value := range opts.Overrides {
+		if err := setKey(&conf, key, value); err != nil {
+			return conf, fmt.Errorf("flag --%s: %w", key, err)
+		}
+	}
+	if err := conf.Validate(); err != nil {
 		return conf, err
 	}
-	config = conf
 	return conf, nil
 }
 
+func readFile(path string, conf *Config) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	// Strict: опечатка в ключе — ошибка с номером строки, а не тихий пропуск
+	return yaml.NewDecoder(file, yaml.Strict()).Decode(conf)
+}
+
+// Redacted возвращает копию без секретов — для вывода --print-config.
+func (c Config) Redacted() Config {
+	const hidden = "<redacted>"
+	if c.Auth.Secret != "" {
+		c.Auth.Secret = hidden
+	}
+	users := make([]UserConfig, len(c.Auth.Users))
+	for i, u := range c.Auth.Users {
+		if u.PasswordHash != "" {
+			u.PasswordHash = hidden
+		}
+		if len(u.Tokens) > 0 {
+			u.Tokens = []string{fmt.Sprintf("<%d redacted>", len(u.Tokens))}
+		}
+		users[i] = u
+	}
+	c.Auth.Users = users
+	return c
+}
+
+func (c Config) YAML() ([]byte, error) {
+	return yaml.Marshal(c)
+}
+
+// Get возвращает действующую конфигурацию; после перезагрузки по SIGHUP —
+// новую.
 func Get() Config {
-	return config
+	if c := current.Load(); c != nil {
+		return *c
+	}
+	return Default()
+}
+
+func Set(c Config) {
+	current.Store(&c)
 }
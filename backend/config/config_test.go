@@ -0,0 +1,195 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "appconfig.yml")
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadLayers(t *testing.T) {
+	path := writeConfig(t, `
+addr: 127.0.0.1:8080
+log_level: warn
+auth:
+  disabled: true
+retention:
+  max_age: 1h
+  max_entries: 100
+`)
+	t.Setenv("TFLOGS_RETENTION_MAX_AGE", "2h")
+	t.Setenv("TFLOGS_HTTP_CORS_ORIGINS", "http://a.example, http://b.example:8080,")
+	t.Setenv("TFLOGS_SERVER_H2C", "true")
+
+	conf, err := Load(LoadOptions{Path: path, Overrides: map[string]string{"log_level": "debug"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checks := []struct {
+		name      string
+		got, want any
+	}{
+		{"default", conf.Snapshot.Keep, 3},
+		{"file", conf.Addr, "127.0.0.1:8080"},
+		{"file not overridden", conf.Retention.MaxEntries, 100},
+		{"env over file", conf.Retention.MaxAge, 2 * time.Hour},
+		{"env bool", conf.Server.H2C, true},
+		{"env list", strings.Join(conf.HTTP.CORSOrigins, " "), "http://a.example http://b.example:8080"},
+		{"flag over file", conf.LogLevel, "debug"},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s: got %v, want %v", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	valid := "auth:\n  disabled: true\n"
+	tests := []struct {
+		name, file string
+		env        map[string]string
+		flags      map[string]string
+		want       string
+	}{
+		{name: "unknown key", file: valid + "retention:\n  max_ages: 1h\n", want: "max_ages"},
+		{name: "wrong type", file: valid + "retention:\n  max_entries: many\n", want: "max_entries"},
+		{name: "env duration", file: valid, env: map[string]string{"TFLOGS_RETENTION_MAX_AGE": "soon"}, want: "TFLOGS_RETENTION_MAX_AGE"},
+		{name: "env bool", file: valid, env: map[string]string{"TFLOGS_AUTH_DISABLED": "maybe"}, want: `TFLOGS_AUTH_DISABLED: invalid boolean "maybe"`},
+		{name: "env int", file: valid, env: map[string]string{"TFLOGS_SNAPSHOT_KEEP": "x"}, want: `TFLOGS_SNAPSHOT_KEEP: invalid integer "x"`},
+		{name: "unknown flag", file: valid, flags: map[string]string{"retention.maxage": "1h"}, want: `flag --retention.maxage: unknown setting`},
+		{name: "validation after layers", file: valid, flags: map[string]string{"snapshot.keep": "0"}, want: "snapshot.keep: must be at least 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			_, err := Load(LoadOptions{Path: writeConfig(t, tt.file), Overrides: tt.flags})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load: %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.yml")
+	t.Setenv("TFLOGS_AUTH_DISABLED", "true")
+	conf, err := Load(LoadOptions{Path: path})
+	if err != nil {
+		t.Fatalf("optional missing file: %v", err)
+	}
+	if conf.Addr != Default().Addr {
+		t.Errorf("addr = %s, want default", conf.Addr)
+	}
+	if _, err := Load(LoadOptions{Path: path, Required: true}); err == nil {
+		t.Error("required missing file: no error")
+	}
+}
+
+func TestKeys(t *testing.T) {
+	byName := make(map[string]Key)
+	for _, k := range Keys() {
+		byName[k.Name] = k
+	}
+	if k := byName["retention.max_age"]; k.Env != "TFLOGS_RETENTION_MAX_AGE" || k.Type != "duration" {
+		t.Errorf("retention.max_age = %+v", k)
+	}
+	if k := byName["http.cors_origins"]; k.Type != "comma-separated list" {
+		t.Errorf("http.cors_origins = %+v", k)
+	}
+	if _, ok := byName["auth.users"]; ok {
+		t.Error("auth.users is settable outside the file")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	user := UserConfig{Name: "ci", Role: "uploader", Tokens: []string{"t-1"}}
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+		want   []string
+	}{
+		{"valid", func(c *Config) {}, nil},
+		{"valid with users", func(c *Config) { c.Auth.Disabled, c.Auth.Users = false, []UserConfig{user} }, nil},
+		{"no users", func(c *Config) { c.Auth.Disabled = false }, []string{"auth.users: required"}},
+		{"disabled with users", func(c *Config) { c.Auth.Users = []UserConfig{user} }, []string{"auth.disabled: excludes auth.users"}},
+		{"addr without port", func(c *Config) { c.Addr = "localhost" }, []string{"addr:"}},
+		{"same grpc addr", func(c *Config) { c.GRPC.Addr = c.Addr }, []string{"grpc.addr: must differ from addr"}},
+		{"log level", func(c *Config) { c.LogLevel = "trace" }, []string{"log_level: must be one of"}},
+		{"wildcard origin", func(c *Config) { c.HTTP.CORSOrigins = []string{"*"} }, []string{"http.cors_origins[0]: wildcard"}},
+		{"origin with path", func(c *Config) { c.HTTP.CORSOrigins = []string{"http://a", "http://b/app"} }, []string{`http.cors_origins[1]: "http://b/app" is not an origin`}},
+		{"page sizes", func(c *Config) { c.HTTP.DefaultPageSize = 2000 }, []string{"http.default_page_size"}},
+		{"tls half set", func(c *Config) { c.Server.TLS.CertFile = "cert.pem" }, []string{"server.tls: cert_file and key_file must be set together"}},
+		{"gate below -1", func(c *Config) { c.Gate.MaxErrors = -2 }, []string{"gate.max_errors: must be -1 (disabled) or more"}},
+		{"evict policy", func(c *Config) { c.Retention.Evict = "random" }, []string{"retention.evict"}},
+		{
+			"users",
+			func(c *Config) {
+				c.Auth.Disabled = false
+				c.Auth.Users = []UserConfig{
+					user,
+					{Name: "ci", Role: "root", Tokens: []string{"t-1"}, Workspaces: []string{"Bad WS"}},
+					{Name: "nobody", Role: "viewer"},
+				}
+			},
+			[]string{
+				"auth.users[1].name: duplicate of auth.users[0]",
+				`auth.users[1].role: must be one of viewer, uploader, admin, got "root"`,
+				`auth.users[1].tokens: token also belongs to "ci"`,
+				`auth.users[1].workspaces[0]: invalid workspace id "Bad WS"`,
+				"auth.users[2]: needs password_hash or tokens",
+			},
+		},
+	}
+	for _, tt := range tests {
+		c := Default()
+		c.Auth.Disabled = true
+		tt.modify(&c)
+		err := c.Validate()
+		if tt.want == nil {
+			if err != nil {
+				t.Errorf("%s: %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("%s: no error, want %q", tt.name, tt.want)
+			continue
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("%s: %v\nwant it to contain %q", tt.name, err, want)
+			}
+		}
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	c := Default()
+	c.Auth.Secret = "s"
+	c.Auth.Users = []UserConfig{{Name: "ci", PasswordHash: "$2a$10$x", Tokens: []string{"t-1", "t-2"}}}
+	data, err := c.Redacted().YAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"$2a$10$x", "t-1", "t-2"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("redacted config contains %q", secret)
+		}
+	}
+	if c.Auth.Users[0].Tokens[0] != "t-1" {
+		t.Error("Redacted changed the original config")
+	}
+}
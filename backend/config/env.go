@@ -0,0 +1,128 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const EnvPrefix = "TFLOGS_"
+
+// Key — настройка, которую можно задать флагом --<Name> или переменной Env.
+type Key struct {
+	Name string // retention.max_age
+	Env  string // TFLOGS_RETENTION_MAX_AGE
+	Type string
+}
+
+var durationType = reflect.TypeFor[time.Duration]()
+
+// Keys перечисляет скалярные настройки и списки строк. Списки структур
+// (auth.users) задаются только в файле.
+func Keys() []Key {
+	var keys []Key
+	walk(reflect.ValueOf(&Config{}).Elem(), "", func(name string, v reflect.Value) {
+		typ := v.Type().String()
+		if v.Type() == durationType {
+			typ = "duration"
+		} else if v.Kind() == reflect.Slice {
+			typ = "comma-separated list"
+		}
+		keys = append(keys, Key{Name: name, Env: envName(name), Type: typ})
+	})
+	return keys
+}
+
+func envName(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+func walk(v reflect.Value, prefix string, fn func(name string, v reflect.Value)) {
+	t := v.Type()
+	for i := range t.NumField() {
+		tag, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		name := prefix + tag
+		f := v.Field(i)
+		switch {
+		case f.Kind() == reflect.Struct:
+			walk(f, name+".", fn)
+		case f.Kind() == reflect.Slice && f.Type().Elem().Kind() != reflect.String:
+			// списки структур — только в файле
+		default:
+			fn(name, f)
+		}
+	}
+}
+
+func applyEnv(conf *Config, lookup func(string) (string, bool)) error {
+	var errs []error
+	walk(reflect.ValueOf(conf).Elem(), "", func(name string, v reflect.Value) {
+		value, ok := lookup(envName(name))
+		if !ok {
+			return
+		}
+		if err := setValue(v, value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", envName(name), err))
+		}
+	})
+	return errors.Join(errs...)
+}
+
+func setKey(conf *Config, key, value string) error {
+	found := false
+	var err error
+	walk(reflect.ValueOf(conf).Elem(), "", func(name string, v reflect.Value) {
+		if name == key {
+			found = true
+			err = setValue(v, value)
+		}
+	})
+	if !found {
+		return fmt.Errorf("unknown setting %q", key)
+	}
+	return err
+}
+
+func setValue(v reflect.Value, s string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", s)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", s)
+		}
+		v.SetInt(n)
+	case reflect.Slice:
+		items := []string{}
+		for item := range strings.SplitSeq(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
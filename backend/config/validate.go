@@ -0,0 +1,184 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"slices"
+	"time"
+
+	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+)
+
+// Validate проверяет конфигурацию и возвращает все найденные ошибки сразу,
+// каждую с путём к настройке.
+func (c Config) Validate() error {
+	var errs []error
+	fail := func(key, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+	}
+
+	if _, port, err := net.SplitHostPort(c.Addr); err != nil {
+		fail("addr", "%v", err)
+	} else if port == "" {
+		fail("addr", "missing port")
+	}
+	if c.GRPC.Addr != "" {
+		if _, port, err := net.SplitHostPort(c.GRPC.Addr); err != nil {
+			fail("grpc.addr", "%v", err)
+		} else if port == "" {
+			fail("grpc.addr", "missing port")
+		} else if c.GRPC.Addr == c.Addr {
+			fail("grpc.addr", "must differ from addr")
+		}
+	}
+	if !slices.Contains([]string{"debug", "info", "warn", "error"}, c.LogLevel) {
+		fail("log_level", "must be one of debug, info, warn, error, got %q", c.LogLevel)
+	}
+
+	timeouts := []struct {
+		key string
+		d   time.Duration
+	}{
+		{"server.read_header_timeout", c.Server.ReadHeaderTimeout},
+		{"server.read_timeout", c.Server.ReadTimeout},
+		{"server.write_timeout", c.Server.WriteTimeout},
+		{"server.idle_timeout", c.Server.IdleTimeout},
+	}
+	for _, t := range timeouts {
+		if t.d < 0 {
+			fail(t.key, "must not be negative, got %s", t.d)
+		}
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		fail("server.shutdown_timeout", "must be positive, got %s", c.Server.ShutdownTimeout)
+	}
+	tls := c.Server.TLS
+	switch {
+	case tls.SelfSigned && (tls.CertFile != "" || tls.KeyFile != ""):
+		fail("server.tls", "self_signed excludes cert_file and key_file")
+	case (tls.CertFile == "") != (tls.KeyFile == ""):
+		fail("server.tls", "cert_file and key_file must be set together")
+	case tls.CertFile != "":
+		if _, err := os.Stat(tls.CertFile); err != nil {
+			fail("server.tls.cert_file", "%v", err)
+		}
+		if _, err := os.Stat(tls.KeyFile); err != nil {
+			fail("server.tls.key_file", "%v", err)
+		}
+	}
+
+	for i, origin := range c.HTTP.CORSOrigins {
+		key := fmt.Sprintf("http.cors_origins[%d]", i)
+		// Запросы идут с cookie сессии: "*" открыл бы API любому сайту
+		if origin == "*" {
+			fail(key, "wildcard is not allowed with credentialed requests, list the origins")
+			continue
+		}
+		if u, err := url.Parse(origin); err != nil || u.Scheme == "" || u.Host == "" || u.Path != "" {
+			fail(key, "%q is not an origin (scheme://host[:port])", origin)
+		}
+	}
+	if c.HTTP.MaxUploadBytes <= 0 {
+		fail("http.max_upload_bytes", "must be positive, got %d", c.HTTP.MaxUploadBytes)
+	}
+	if c.HTTP.MaxPageSize <= 0 {
+		fail("http.max_page_size", "must be positive, got %d", c.HTTP.MaxPageSize)
+	}
+	if c.HTTP.DefaultPageSize <= 0 || c.HTTP.DefaultPageSize > c.HTTP.MaxPageSize {
+		fail("http.default_page_size", "must be in 1..http.max_page_size (%d), got %d",
+			c.HTTP.MaxPageSize, c.HTTP.DefaultPageSize)
+	}
+	if c.HTTP.MaxContextLines <= 0 {
+		fail("http.max_context_lines", "must be positive, got %d", c.HTTP.MaxContextLines)
+	}
+
+	if c.Auth.SessionTTL <= 0 {
+		fail("auth.session_ttl", "must be positive, got %s", c.Auth.SessionTTL)
+	}
+	switch {
+	case !c.Auth.Disabled && len(c.Auth.Users) == 0:
+		fail("auth.users", "required; set auth.disabled: true to run without authentication (everyone is admin)")
+	case c.Auth.Disabled && len(c.Auth.Users) > 0:
+		fail("auth.disabled", "excludes auth.users")
+	}
+	names := make(map[string]int)
+	tokens := make(map[string]string)
+	for i, u := range c.Auth.Users {
+		key := fmt.Sprintf("auth.users[%d]", i)
+		if u.Name == "" {
+			fail(key+".name", "required")
+		} else if j, ok := names[u.Name]; ok {
+			fail(key+".name", "duplicate of auth.users[%d]", j)
+		}
+		names[u.Name] = i
+		if !slices.Contains([]string{"viewer", "uploader", "admin"}, u.Role) {
+			fail(key+".role", "must be one of viewer, uploader, admin, got %q", u.Role)
+		}
+		if u.PasswordHash == "" && len(u.Tokens) == 0 {
+			fail(key, "needs password_hash or tokens to be able to sign in")
+		}
+		for _, t := range u.Tokens {
+			if owner, ok := tokens[t]; ok {
+				fail(key+".tokens", "token also belongs to %q", owner)
+			}
+			tokens[t] = u.Name
+		}
+		for j, ws := range u.Workspaces {
+			if ws != "*" && !log.ValidWorkspaceID(ws) {
+				fail(fmt.Sprintf("%s.workspaces[%d]", key, j), "invalid workspace id %q", ws)
+			}
+		}
+	}
+
+	if c.Retention.MaxAge < 0 {
+		fail("retention.max_age", "must not be negative")
+	}
+	if c.Retention.MaxEntries < 0 {
+		fail("retention.max_entries", "must not be negative")
+	}
+	if c.Retention.MaxMemoryBytes < 0 {
+		fail("retention.max_memory_bytes", "must not be negative")
+	}
+	if c.Retention.Evict != "oldest" && c.Retention.Evict != "lru" {
+		fail("retention.evict", "must be oldest or lru, got %q", c.Retention.Evict)
+	}
+	if c.Retention.Interval <= 0 {
+		fail("retention.interval", "must be positive, got %s", c.Retention.Interval)
+	}
+	if c.Snapshot.Keep < 1 {
+		fail("snapshot.keep", "must be at least 1, got %d", c.Snapshot.Keep)
+	}
+	gateCounts := []struct {
+		key string
+		n   int
+	}{
+		{"gate.max_errors", c.Gate.MaxErrors},
+		{"gate.max_http_5xx", c.Gate.MaxHTTP5xx},
+		{"gate.max_retries", c.Gate.MaxRetries},
+	}
+	for _, g := range gateCounts {
+		if g.n < -1 {
+			fail(g.key, "must be -1 (disabled) or more, got %d", g.n)
+		}
+	}
+	if c.Gate.MaxRPCDuration < 0 {
+		fail("gate.max_rpc_duration", "must not be negative")
+	}
+	if c.Gate.MaxHTTPDuration < 0 {
+		fail("gate.max_http_duration", "must not be negative")
+	}
+	if c.Plugins.Timeout <= 0 {
+		fail("plugins.timeout", "must be positive, got %s", c.Plugins.Timeout)
+	}
+	if c.Plugins.Dir != "" {
+		if fi, err := os.Stat(c.Plugins.Dir); err != nil {
+			fail("plugins.dir", "%v", err)
+		} else if !fi.IsDir() {
+			fail("plugins.dir", "%s is not a directory", c.Plugins.Dir)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,181 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Поля запроса по телам HTTP: req_body.<путь> и res_body.<путь>, путь —
+// ключи через точку и индексы массивов (items[0].id или items.0.id).
+const (
+	reqBodyField = "req_body"
+	resBodyField = "res_body"
+)
+
+// ParseBodies разбирает tf_http_req_body и tf_http_res_body как JSON. Тела
+// не в JSON (пустые, form, текст ошибки прокси) остаются только строками.
+func (l *Log) ParseBodies() {
+	l.ReqBody, l.ResBody = parseBody(l.Tf_http_req_body), parseBody(l.Tf_http_res_body)
+}
+
+func parseBody(s string) any {
+	s = strings.TrimSpace(s)
+	if s == "" || s[0] != '{' && s[0] != '[' {
+		return nil
+	}
+	dec := json.NewDecoder(strings.NewReader(s))
+	// Числа как есть: ID и размеры не теряют точность в float64
+	dec.UseNumber()
+	var v any
+	if dec.Decode(&v) != nil || dec.More() {
+		return nil
+	}
+	return v
+}
+
+// bodyField — значение по пути в разобранном теле. Шаг, не являющийся
+// индексом, применяется к каждому элементу массива; несколько найденных
+// значений соединяются через запятую, как у полей-списков. null считается
+// отсутствующим значением.
+func bodyField(body func(l *Log) any, path string) fieldGetter {
+	steps := splitBodyPath(path)
+	return func(l *Log) (string, bool) {
+		var vals []string
+		collectBody(body(l), steps, &vals)
+		return strings.Join(vals, ","), len(vals) > 0
+	}
+}
+
+// bodyPath отделяет путь от имени поля: res_body.error.code, res_body[0].id
+// или само res_body.
+func bodyPath(name, root string) (string, bool) {
+	rest, ok := strings.CutPrefix(name, root)
+	switch {
+	case !ok:
+		return "", false
+	case rest == "", rest[0] == '[':
+		return rest, true
+	case rest[0] == '.':
+		return rest[1:], true
+	}
+	return "", false
+}
+
+func splitBodyPath(path string) []string {
+	var steps []string
+	for _, part := range strings.Split(path, ".") {
+		for part != "" {
+			i := strings.IndexByte(part, '[')
+			if i < 0 {
+				steps = append(steps, part)
+				break
+			}
+			if i > 0 {
+				steps = append(steps, part[:i])
+			}
+			j := strings.IndexByte(part[i:], ']')
+			if j < 0 {
+				steps = append(steps, part[i+1:])
+				break
+			}
+			steps = append(steps, part[i+1:i+j])
+			part = part[i+j+1:]
+		}
+	}
+	return steps
+}
+
+func collectBody(v any, steps []string, out *[]string) {
+	if v == nil {
+		return
+	}
+	if len(steps) == 0 {
+		*out = append(*out, bodyString(v))
+		return
+	}
+	switch node := v.(type) {
+	case map[string]any:
+		collectBody(node[steps[0]], steps[1:], out)
+	case []any:
+		if i, err := strconv.Atoi(steps[0]); err == nil {
+			if i >= 0 && i < len(node) {
+				collectBody(node[i], steps[1:], out)
+			}
+			return
+		}
+		for _, elem := range node {
+			collectBody(elem, steps, out)
+		}
+	}
+}
+
+func bodyString(v any) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case json.Number:
+		return s.String()
+	case bool:
+		return strconv.FormatBool(s)
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// DefaultBodyPreview — сколько байт тела показывать в GET /logs/{id}.
+const DefaultBodyPreview = 64 << 10
+
+// Body — тело HTTP для просмотра: JSON с отступами, если разбирается.
+type Body struct {
+	Size      int    `json:"size"` // байт в исходной записи
+	JSON      bool   `json:"json"`
+	Text      string `json:"text"`
+	Truncated bool   `json:"truncated"`
+}
+
+// LogBodies — тела запроса и ответа записи.
+type LogBodies struct {
+	Request  *Body `json:"request,omitempty"`
+	Response *Body `json:"response,omitempty"`
+}
+
+// LogDetail — запись с телами для просмотра.
+type LogDetail struct {
+	Log
+	Bodies *LogBodies `json:"bodies,omitempty"`
+}
+
+// NewLogDetail форматирует тела записи; текст длиннее limit байт (limit <= 0 —
+// без ограничения) обрезается с пометкой о показанной части.
+func NewLogDetail(l Log, limit int) LogDetail {
+	d := LogDetail{Log: l}
+	req, res := viewBody(l.Tf_http_req_body, limit), viewBody(l.Tf_http_res_body, limit)
+	if req != nil || res != nil {
+		d.Bodies = &LogBodies{Request: req, Response: res}
+	}
+	return d
+}
+
+func viewBody(s string, limit int) *Body {
+	if s == "" {
+		return nil
+	}
+	b := &Body{Size: len(s), Text: s}
+	var buf bytes.Buffer
+	if parseBody(s) != nil && json.Indent(&buf, []byte(strings.TrimSpace(s)), "", "  ") == nil {
+		b.JSON, b.Text = true, buf.String()
+	}
+	if limit > 0 && len(b.Text) > limit {
+		cut := limit
+		// Не резать многобайтовый символ
+		for cut > 0 && b.Text[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.Text = fmt.Sprintf("%s\n… [truncated: %d of %d bytes shown]", b.Text[:cut], cut, len(b.Text))
+		b.Truncated = true
+	}
+	return b
+}
@@ -0,0 +1,124 @@
+package log
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Diagnostic — структурированные поля, извлечённые из diagnostic_detail
+// и diagnostic_attribute.
+type Diagnostic struct {
+	HTTPStatus     int             `json:"http_status,omitempty"`
+	HTTPStatusText string          `json:"http_status_text,omitempty"`
+	RequestID      string          `json:"request_id,omitempty"`
+	RequestTime    string          `json:"request_time,omitempty"`
+	ErrorCode      string          `json:"error_code,omitempty"`
+	Body           json.RawMessage `json:"body,omitempty"`
+	AttributePath  string          `json:"attribute_path,omitempty"`
+}
+
+var (
+	statusLineRe = regexp.MustCompile(`^([1-5]\d\d)\s+(.+)$`)
+	attrStepRe   = regexp.MustCompile(`(AttributeName|ElementKeyString|ElementKeyInt|ElementKeyValue)\(([^)]*)\)`)
+)
+
+func ParseDiagnostic(l Log) *Diagnostic {
+	if l.Diagnostic_detail == "" && l.Diagnostic_attribute == "" {
+		return nil
+	}
+	var d Diagnostic
+	lines := strings.Split(l.Diagnostic_detail, "\n")
+loop:
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "HttpRequestTimeUTC:"):
+			d.RequestTime = strings.TrimSpace(strings.TrimPrefix(line, "HttpRequestTimeUTC:"))
+		case strings.HasPrefix(line, "HttpRequestId:"):
+			d.RequestID = strings.TrimSpace(strings.TrimPrefix(line, "HttpRequestId:"))
+		case strings.HasPrefix(line, "Body:"):
+			// Тело может занимать несколько строк — берём всё до конца
+			rest := strings.TrimPrefix(line, "Body:")
+			if i+1 < len(lines) {
+				rest += "\n" + strings.Join(lines[i+1:], "\n")
+			}
+			rest = strings.TrimSpace(rest)
+			if json.Valid([]byte(rest)) {
+				d.Body = json.RawMessage(rest)
+				d.ErrorCode = errorCodeFromBody(d.Body)
+			}
+			break loop
+		case d.HTTPStatus == 0 && statusLineRe.MatchString(line):
+			m := statusLineRe.FindStringSubmatch(line)
+			d.HTTPStatus, _ = strconv.Atoi(m[1])
+			d.HTTPStatusText = m[2]
+		}
+	}
+	d.AttributePath = ParseAttributePath(l.Diagnostic_attribute)
+	return &d
+}
+
+// ParseAttributePath переводит путь вида
+// AttributeName("network_interface_ids").ElementKeyInt(0) в network_interface_ids[0].
+func ParseAttributePath(attr string) string {
+	var b strings.Builder
+	for _, m := range attrStepRe.FindAllStringSubmatch(attr, -1) {
+		arg := m[2]
+		switch m[1] {
+		case "AttributeName":
+			if unq, err := strconv.Unquote(arg); err == nil {
+				arg = unq
+			}
+			if b.Len() > 0 {
+				b.WriteByte('.')
+			}
+			b.WriteString(arg)
+		default:
+			b.WriteString("[" + arg + "]")
+		}
+	}
+	if b.Len() == 0 {
+		return attr
+	}
+	return b.String()
+}
+
+func errorCodeFromBody(body json.RawMessage) string {
+	var v struct {
+		Error any    `json:"error"`
+		Code  any    `json:"code"`
+		Type  string `json:"type"`
+	}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return ""
+	}
+	switch e := v.Error.(type) {
+	case string:
+		return e
+	case map[string]any:
+		if code, ok := e["code"]; ok {
+			return anyToString(code)
+		}
+		if typ, ok := e["type"].(string); ok {
+			return typ
+		}
+	}
+	if v.Code != nil {
+		return anyToString(v.Code)
+	}
+	return v.Type
+}
+
+func anyToString(v any) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	default:
+		b, _ := json.Marshal(t)
+		return string(b)
+	}
+}
@@ -0,0 +1,116 @@
+package log
+
+import (
+	"cmp"
+	"slices"
+	"sort"
+	"strconv"
+)
+
+// Latency — перцентили задержки в миллисекундах (nearest-rank).
+type Latency struct {
+	Samples int `json:"samples"`
+	P50     int `json:"p50"`
+	P95     int `json:"p95"`
+	P99     int `json:"p99"`
+	Max     int `json:"max"`
+}
+
+// EndpointStats — вызовы API облака одним методом по одному шаблону URI.
+type EndpointStats struct {
+	Method   string      `json:"method"`
+	Template string      `json:"template"`
+	Count    int         `json:"count"`
+	Statuses map[int]int `json:"statuses"`
+	// Запросы без записи ответа (обрыв, таймаут, лог обрезан)
+	NoResponse int            `json:"no_response"`
+	RPCs       map[string]int `json:"rpcs"`
+	// Latency — от запроса до ответа по записям транзакции; Kong* — из
+	// заголовков X-Kong-Upstream-Latency и X-Kong-Proxy-Latency ответа.
+	Latency      Latency `json:"latency"`
+	KongUpstream Latency `json:"kong_upstream"`
+	KongProxy    Latency `json:"kong_proxy"`
+}
+
+// EndpointAnalytics группирует HTTP-транзакции по методу и шаблону URI
+// (NormalizeURI); самые частые эндпоинты идут первыми.
+func EndpointAnalytics(logs []Log) []EndpointStats {
+	type acc struct {
+		stats                    *EndpointStats
+		latency, upstream, proxy []int
+	}
+	byKey := make(map[string]*acc)
+	for _, t := range HTTPTransactions(logs) {
+		if t.Request == nil {
+			continue
+		}
+		template := NormalizeURI(t.URI)
+		a, ok := byKey[t.Method+" "+template]
+		if !ok {
+			a = &acc{stats: &EndpointStats{
+				Method:   t.Method,
+				Template: template,
+				Statuses: make(map[int]int),
+				RPCs:     make(map[string]int),
+			}}
+			byKey[t.Method+" "+template] = a
+		}
+		s := a.stats
+		s.Count++
+		if t.RPC != "" {
+			s.RPCs[t.RPC]++
+		}
+		if t.Response == nil {
+			s.NoResponse++
+			continue
+		}
+		if t.Status != 0 {
+			s.Statuses[t.Status]++
+		}
+		if !t.RequestAt.IsZero() && !t.ResponseAt.IsZero() {
+			a.latency = append(a.latency, t.DurationMs)
+		}
+		if ms, err := strconv.Atoi(t.Response.X_Kong_Upstream_Latency); err == nil {
+			a.upstream = append(a.upstream, ms)
+		}
+		if ms, err := strconv.Atoi(t.Response.X_Kong_Proxy_Latency); err == nil {
+			a.proxy = append(a.proxy, ms)
+		}
+	}
+	result := make([]EndpointStats, 0, len(byKey))
+	for _, a := range byKey {
+		a.stats.Latency = latency(a.latency)
+		a.stats.KongUpstream = latency(a.upstream)
+		a.stats.KongProxy = latency(a.proxy)
+		result = append(result, *a.stats)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		return cmp.Or(
+			cmp.Compare(b.Count, a.Count),
+			cmp.Compare(a.Template, b.Template),
+			cmp.Compare(a.Method, b.Method),
+		) < 0
+	})
+	return result
+}
+
+func latency(ms []int) Latency {
+	if len(ms) == 0 {
+		return Latency{}
+	}
+	slices.Sort(ms)
+	return Latency{
+		Samples: len(ms),
+		P50:     percentile(ms, 50),
+		P95:     percentile(ms, 95),
+		P99:     percentile(ms, 99),
+		Max:     ms[len(ms)-1],
+	}
+}
+
+// percentile по отсортированным значениям, nearest-rank.
+func percentile(sorted []int, p int) int {
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
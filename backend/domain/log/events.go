@@ -0,0 +1,9 @@
+package log
+
+// UploadEvent — новая загрузка: метаданные и добавленные ею записи.
+// Записи — копии, подписчик может читать их без блокировок.
+type UploadEvent struct {
+	Workspace string
+	Upload    Upload
+	Logs      []Log
+}
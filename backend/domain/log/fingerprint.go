@@ -0,0 +1,60 @@
+package log
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"strings"
+)
+
+// Severity возвращает "error" или "warning" для проблемных записей и
+// пустую строку для остальных.
+func Severity(l Log) string {
+	switch strings.ToLower(l.Diagnostic_severity) {
+	case "error":
+		return "error"
+	case "warning":
+		return "warning"
+	}
+	switch strings.ToLower(l.At_level) {
+	case "error":
+		return "error"
+	case "warn", "warning":
+		return "warning"
+	}
+	if l.At_level == "" {
+		switch l.Level {
+		case 1:
+			return "warning"
+		case 2:
+			return "error"
+		}
+	}
+	return ""
+}
+
+func ResourceType(l Log) string {
+	if l.Tf_resource_type != "" {
+		return l.Tf_resource_type
+	}
+	if l.Tf_data_source_type != "" {
+		return "data." + l.Tf_data_source_type
+	}
+	return ""
+}
+
+// Fingerprint — отпечаток ошибки/предупреждения, не зависящий от UUID,
+// путей и времени: шаблон сообщения, тип ресурса, код ошибки облака и
+// нормализованный summary. Требует заполненного TemplateID.
+func Fingerprint(l Log) string {
+	sev := Severity(l)
+	if sev == "" {
+		return ""
+	}
+	parts := []string{sev, ResourceType(l), l.Tf_rpc, l.TemplateID}
+	if l.Diag != nil {
+		parts = append(parts, l.Diag.ErrorCode, l.Diag.AttributePath)
+	}
+	parts = append(parts, strings.Join(TokenizeMessage(l.Diagnostic_summary), " "))
+	sum := sha1.Sum([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
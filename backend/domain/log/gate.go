@@ -0,0 +1,348 @@
+package log
+
+import (
+	"cmp"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// GateThresholds — пороги CI-проверки запуска. Счётчики сравниваются с
+// общим числом по запуску, отрицательное значение отключает проверку;
+// длительности проверяются для каждого вызова, 0 отключает проверку.
+type GateThresholds struct {
+	MaxErrors       int           `json:"max_errors"`
+	MaxHTTP5xx      int           `json:"max_http_5xx"`
+	MaxRetries      int           `json:"max_retries"`
+	MaxRPCDuration  time.Duration `json:"-"`
+	MaxHTTPDuration time.Duration `json:"-"`
+}
+
+// DefaultGateThresholds проваливает запуск на любой ошибке или ответе 5xx.
+func DefaultGateThresholds() GateThresholds {
+	return GateThresholds{MaxErrors: 0, MaxHTTP5xx: 0, MaxRetries: -1}
+}
+
+const (
+	GateErrors       = "error"
+	GateHTTP5xx      = "http_5xx"
+	GateRetry        = "retry"
+	GateRPCDuration  = "rpc_duration"
+	GateHTTPDuration = "http_duration"
+)
+
+// GateFailure — одно нарушение порога.
+type GateFailure struct {
+	Kind      string `json:"kind"`
+	Message   string `json:"message"`
+	Detail    string `json:"detail,omitempty"`
+	Timestamp string `json:"timestamp,omitempty"`
+	TFReqID   string `json:"tf_req_id,omitempty"`
+}
+
+// GateCase — результат проверки одного ресурса; записи без адреса
+// относятся к случаю GateRunCase.
+type GateCase struct {
+	Name       string        `json:"name"`
+	Type       string        `json:"type,omitempty"`
+	DurationMs int           `json:"duration_ms"`
+	Failures   []GateFailure `json:"failures"`
+}
+
+const GateRunCase = "terraform"
+
+type GateCounts struct {
+	Errors   int `json:"errors"`
+	HTTP5xx  int `json:"http_5xx"`
+	Retries  int `json:"retries"`
+	SlowRPC  int `json:"slow_rpcs"`
+	SlowHTTP int `json:"slow_http"`
+}
+
+type GateReport struct {
+	Name       string     `json:"name"`
+	Passed     bool       `json:"passed"`
+	Timestamp  time.Time  `json:"timestamp"`
+	DurationMs int        `json:"duration_ms"`
+	Counts     GateCounts `json:"counts"`
+	Cases      []GateCase `json:"cases"`
+}
+
+type gateHit struct {
+	addr string
+	f    GateFailure
+}
+
+var retryMessageRe = regexp.MustCompile(`(?i)\bretr(y|ying|ies)\b`)
+
+// EvaluateGate проверяет запуск по порогам. Нарушения счётчиков попадают
+// в те ресурсы, где они случились, — но только если общий счётчик
+// превысил порог.
+func EvaluateGate(name string, logs []Log, t GateThresholds) GateReport {
+	byType := ResourceAddresses(logs)
+	cases := make(map[string]*GateCase)
+	caseFor := func(addr string) *GateCase {
+		if addr == "" {
+			addr = GateRunCase
+		}
+		c, ok := cases[addr]
+		if !ok {
+			c = &GateCase{Name: addr, Type: addressType(addr), Failures: []GateFailure{}}
+			cases[addr] = c
+		}
+		return c
+	}
+	for addr := range RunResources(logs) {
+		caseFor(addr)
+	}
+
+	var errors, http5xx, retries []gateHit
+	add := func(list *[]gateHit, addr string, f GateFailure) {
+		*list = append(*list, gateHit{addr, f})
+	}
+
+	// Повтор запроса: тот же метод и URI в рамках RPC после ответа 429/5xx
+	failedReq := make(map[string]bool)
+	for _, tx := range HTTPTransactions(logs) {
+		key := tx.TFReqID + " " + tx.Method + " " + tx.URI
+		if failedReq[key] && tx.Request != nil {
+			add(&retries, ResourceAddress(*tx.Request, byType), GateFailure{
+				Kind:      GateRetry,
+				Message:   fmt.Sprintf("retried %s %s", tx.Method, tx.URI),
+				Timestamp: tx.Request.At_timestamp,
+				TFReqID:   tx.TFReqID,
+			})
+		}
+		failedReq[key] = tx.Status == 429 || tx.Status >= 500
+	}
+
+	for _, l := range logs {
+		addr := ResourceAddress(l, byType)
+		if Severity(l) == "error" {
+			msg := strings.TrimSpace(l.Diagnostic_summary)
+			if msg == "" {
+				msg = firstLine(l.At_message)
+			}
+			add(&errors, addr, GateFailure{
+				Kind:      GateErrors,
+				Message:   msg,
+				Detail:    strings.TrimSpace(l.Diagnostic_detail),
+				Timestamp: l.At_timestamp,
+				TFReqID:   l.Tf_req_id,
+			})
+		}
+		if l.Tf_http_op_type == "response" && l.Tf_http_res_status_code >= 500 {
+			add(&http5xx, addr, GateFailure{
+				Kind:      GateHTTP5xx,
+				Message:   fmt.Sprintf("HTTP %d %s", l.Tf_http_res_status_code, l.Tf_http_res_status_reason),
+				Detail:    l.Tf_http_res_body,
+				Timestamp: l.At_timestamp,
+				TFReqID:   l.Tf_req_id,
+			})
+		}
+		if l.Tf_http_op_type == "" && retryMessageRe.MatchString(l.At_message) {
+			add(&retries, addr, GateFailure{
+				Kind:      GateRetry,
+				Message:   firstLine(l.At_message),
+				Timestamp: l.At_timestamp,
+				TFReqID:   l.Tf_req_id,
+			})
+		}
+	}
+
+	report := GateReport{
+		Name: name,
+		Counts: GateCounts{
+			Errors:  len(errors),
+			HTTP5xx: len(http5xx),
+			Retries: len(retries),
+		},
+	}
+	for _, check := range []struct {
+		max  int
+		list []gateHit
+	}{
+		{t.MaxErrors, errors},
+		{t.MaxHTTP5xx, http5xx},
+		{t.MaxRetries, retries},
+	} {
+		if check.max < 0 || len(check.list) <= check.max {
+			continue
+		}
+		for _, v := range check.list {
+			c := caseFor(v.addr)
+			c.Failures = append(c.Failures, v.f)
+		}
+	}
+
+	for _, call := range RPCCalls(logs) {
+		if call.RPC == "GetProviderSchema" {
+			continue
+		}
+		c := caseFor(call.Resource)
+		c.DurationMs += call.DurationMs
+		if t.MaxRPCDuration > 0 && time.Duration(call.DurationMs)*time.Millisecond > t.MaxRPCDuration {
+			report.Counts.SlowRPC++
+			c.Failures = append(c.Failures, GateFailure{
+				Kind:      GateRPCDuration,
+				Message:   fmt.Sprintf("%s took %dms, limit %s", call.RPC, call.DurationMs, t.MaxRPCDuration),
+				Timestamp: call.Start.Format(time.RFC3339Nano),
+				TFReqID:   call.TFReqID,
+			})
+		}
+	}
+	if t.MaxHTTPDuration > 0 {
+		for _, tx := range HTTPTransactions(logs) {
+			if tx.Request == nil || time.Duration(tx.DurationMs)*time.Millisecond <= t.MaxHTTPDuration {
+				continue
+			}
+			report.Counts.SlowHTTP++
+			c := caseFor(ResourceAddress(*tx.Request, byType))
+			c.Failures = append(c.Failures, GateFailure{
+				Kind:      GateHTTPDuration,
+				Message:   fmt.Sprintf("%s %s took %dms, limit %s", tx.Method, tx.URI, tx.DurationMs, t.MaxHTTPDuration),
+				Timestamp: tx.Request.At_timestamp,
+				TFReqID:   tx.TFReqID,
+			})
+		}
+	}
+
+	var end time.Time
+	for _, l := range logs {
+		if at, ok := Time(l); ok {
+			if report.Timestamp.IsZero() || at.Before(report.Timestamp) {
+				report.Timestamp = at
+			}
+			if at.After(end) {
+				end = at
+			}
+		}
+	}
+	if !end.IsZero() {
+		report.DurationMs = int(end.Sub(report.Timestamp).Milliseconds())
+	}
+	report.Passed = true
+	for _, c := range cases {
+		// Служебный случай показываем, только если в нём что-то нашлось
+		if c.Name == GateRunCase && len(c.Failures) == 0 {
+			continue
+		}
+		report.Cases = append(report.Cases, *c)
+		if len(c.Failures) > 0 {
+			report.Passed = false
+		}
+	}
+	sort.Slice(report.Cases, func(i, j int) bool { return report.Cases[i].Name < report.Cases[j].Name })
+	if report.Cases == nil {
+		report.Cases = []GateCase{}
+	}
+	return report
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Time      string      `xml:"time,attr"`
+	Timestamp string      `xml:"timestamp,attr,omitempty"`
+	Cases     []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",cdata"`
+}
+
+func junitSeconds(ms int) string {
+	return fmt.Sprintf("%.3f", float64(ms)/1000)
+}
+
+// JUnit выводит отчёт в формате JUnit XML: ресурс — testcase, все его
+// нарушения — в одном failure (GitLab показывает только первый).
+func (r GateReport) JUnit() ([]byte, error) {
+	suite := junitSuite{
+		Name:  r.Name,
+		Tests: len(r.Cases),
+		Time:  junitSeconds(r.DurationMs),
+		Cases: make([]junitCase, 0, len(r.Cases)),
+	}
+	if !r.Timestamp.IsZero() {
+		suite.Timestamp = r.Timestamp.UTC().Format("2006-01-02T15:04:05")
+	}
+	for _, c := range r.Cases {
+		jc := junitCase{
+			Name:      c.Name,
+			ClassName: cmp.Or(c.Type, GateRunCase),
+			Time:      junitSeconds(c.DurationMs),
+		}
+		if len(c.Failures) > 0 {
+			suite.Failures++
+			kinds := make([]string, 0, len(c.Failures))
+			var text strings.Builder
+			for i, f := range c.Failures {
+				if !slices.Contains(kinds, f.Kind) {
+					kinds = append(kinds, f.Kind)
+				}
+				if i > 0 {
+					text.WriteString("\n")
+				}
+				fmt.Fprintf(&text, "[%s] %s", f.Kind, f.Message)
+				if f.Timestamp != "" {
+					fmt.Fprintf(&text, "\n  at %s", f.Timestamp)
+				}
+				if f.TFReqID != "" {
+					fmt.Fprintf(&text, "\n  tf_req_id %s", f.TFReqID)
+				}
+				for line := range strings.SplitSeq(f.Detail, "\n") {
+					if line != "" {
+						text.WriteString("\n  " + line)
+					}
+				}
+			}
+			jc.Failure = &junitFailure{
+				Message: c.Failures[0].Message,
+				Type:    strings.Join(kinds, ","),
+				Text:    text.String(),
+			}
+		}
+		suite.Cases = append(suite.Cases, jc)
+	}
+	doc := junitSuites{
+		Name:     "terraform",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Time:     suite.Time,
+		Suites:   []junitSuite{suite},
+	}
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(out, '\n')...), nil
+}
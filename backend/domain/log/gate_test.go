@@ -0,0 +1,73 @@
+package log
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func gateLogs() []Log {
+	return []Log{
+		{At_level: "info", At_message: "Starting apply", At_timestamp: "2025-09-09T15:30:00.000000+03:00"},
+		{
+			At_level: "debug", At_message: "Sending HTTP Request", At_timestamp: "2025-09-09T15:30:01.000000+03:00",
+			Tf_http_trans_id: "t-1", Tf_http_op_type: "request", Tf_http_req_method: "GET", Tf_http_req_uri: "/v1/vips",
+		},
+		{
+			At_level: "debug", At_message: "Received HTTP Response", At_timestamp: "2025-09-09T15:30:03.000000+03:00",
+			Tf_http_trans_id: "t-1", Tf_http_op_type: "response", Tf_http_res_status_code: 502,
+		},
+		{At_level: "warn", At_message: "Retrying request after 502", At_timestamp: "2025-09-09T15:30:04.000000+03:00"},
+		{At_level: "error", At_message: "Error: quota exceeded", At_timestamp: "2025-09-09T15:30:05.000000+03:00"},
+	}
+}
+
+func TestEvaluateGate(t *testing.T) {
+	tests := []struct {
+		name   string
+		t      GateThresholds
+		passed bool
+		kinds  string // виды нарушений через запятую, по алфавиту
+	}{
+		{"zero fails on any", GateThresholds{0, 0, 0, 0, 0}, false, "error,http_5xx,retry"},
+		{"count equal to max passes", GateThresholds{1, 1, 1, 0, 0}, true, ""},
+		{"-1 disables all", GateThresholds{-1, -1, -1, 0, 0}, true, ""},
+		{"errors only", GateThresholds{0, -1, -1, 0, 0}, false, "error"},
+		{"5xx only", GateThresholds{-1, 0, -1, 0, 0}, false, "http_5xx"},
+		{"retries only", GateThresholds{-1, -1, 0, 0, 0}, false, "retry"},
+		{"defaults ignore retries", DefaultGateThresholds(), false, "error,http_5xx"},
+		{"slow HTTP", GateThresholds{-1, -1, -1, 0, time.Second}, false, "http_duration"},
+		{"HTTP within limit", GateThresholds{-1, -1, -1, 0, 2 * time.Second}, true, ""},
+	}
+	for _, tt := range tests {
+		report := EvaluateGate("apply", gateLogs(), tt.t)
+		var kinds []string
+		for _, c := range report.Cases {
+			for _, f := range c.Failures {
+				if !slices.Contains(kinds, f.Kind) {
+					kinds = append(kinds, f.Kind)
+				}
+			}
+		}
+		slices.Sort(kinds)
+		if report.Passed != tt.passed || strings.Join(kinds, ",") != tt.kinds {
+			t.Errorf("%s: passed %v with %v, want %v with %s", tt.name, report.Passed, kinds, tt.passed, tt.kinds)
+		}
+		// Счётчики не зависят от порогов
+		if c := report.Counts; c.Errors != 1 || c.HTTP5xx != 1 || c.Retries != 1 {
+			t.Errorf("%s: counts %+v", tt.name, c)
+		}
+	}
+
+	report := EvaluateGate("apply", gateLogs(), DefaultGateThresholds())
+	if report.DurationMs != 5000 || report.Name != "apply" {
+		t.Errorf("report %s duration %dms, want apply 5000ms", report.Name, report.DurationMs)
+	}
+	if len(report.Cases) != 1 || report.Cases[0].Name != GateRunCase {
+		t.Errorf("cases = %+v, want only %s", report.Cases, GateRunCase)
+	}
+	if clean := EvaluateGate("plan", gateLogs()[:1], GateThresholds{}); !clean.Passed || len(clean.Cases) != 0 {
+		t.Errorf("clean run = %+v, want passed without cases", clean)
+	}
+}
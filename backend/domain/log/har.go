@@ -0,0 +1,205 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// HAR 1.2 (http://www.softwareishard.com/blog/har-12-spec/) — формат
+// выгрузки HTTP-транзакций для devtools браузера и просмотрщиков HAR.
+type harFile struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	Comment         string      `json:"comment,omitempty"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// headerFields — поля записи с заголовками HTTP: теги JSON с заглавной
+// буквы без "_" (Content-Type, X-Request-Id).
+var headerFields []int
+
+func init() {
+	t := reflect.TypeFor[Log]()
+	for i := range t.NumField() {
+		tag, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if tag != "" && unicode.IsUpper(rune(tag[0])) && !strings.Contains(tag, "_") {
+			headerFields = append(headerFields, i)
+		}
+	}
+}
+
+// HAR выгружает транзакции в HAR 1.2. Заголовки, параметры query string
+// и форм, ключи JSON-тел с секретами скрываются (см. redact.go). Транзакции без
+// записи запроса пропускаются, без ответа — выгружаются со статусом 0.
+func HAR(transactions []HTTPTransaction) ([]byte, error) {
+	file := harFile{Log: harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: "terraform-logs-viewer", Version: "1.0"},
+		Entries: make([]harEntry, 0, len(transactions)),
+	}}
+	for _, t := range transactions {
+		if t.Request == nil {
+			continue
+		}
+		file.Log.Entries = append(file.Log.Entries, harTransaction(t))
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(file); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func harTransaction(t HTTPTransaction) harEntry {
+	req := t.Request
+	uri := RedactURI(t.URI)
+	e := harEntry{
+		StartedDateTime: t.RequestAt.Format(time.RFC3339Nano),
+		Request: harRequest{
+			Method:      t.Method,
+			URL:         uri,
+			HTTPVersion: req.Tf_http_req_version,
+			Cookies:     []harNameValue{},
+			Headers:     harHeaders(req),
+			QueryString: []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    len(req.Tf_http_req_body),
+		},
+		Response: harResponse{
+			Cookies:     []harNameValue{},
+			Headers:     []harNameValue{},
+			Content:     harContent{MimeType: "x-unknown"},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+		Comment: strings.TrimSpace(fmt.Sprintf("%s tf_req_id=%s", t.RPC, t.TFReqID)),
+	}
+	if req.Host != "" {
+		e.Request.URL = "https://" + req.Host + uri
+	}
+	if u, err := url.Parse(uri); err == nil {
+		for name, vals := range u.Query() {
+			for _, v := range vals {
+				e.Request.QueryString = append(e.Request.QueryString, harNameValue{Name: name, Value: v})
+			}
+		}
+	}
+	if body := req.Tf_http_req_body; body != "" {
+		e.Request.PostData = &harPostData{MimeType: req.Content_Type, Text: RedactBody(body, req.Content_Type)}
+	}
+
+	res := t.Response
+	if res == nil {
+		e.Comment += " (no response logged)"
+		return e
+	}
+	body := RedactBody(res.Tf_http_res_body, res.Content_Type)
+	e.Response.Status = t.Status
+	e.Response.StatusText = strings.TrimSpace(strings.TrimPrefix(res.Tf_http_res_status_reason, fmt.Sprint(t.Status)))
+	e.Response.HTTPVersion = res.Tf_http_res_version
+	e.Response.Headers = harHeaders(res)
+	e.Response.Content = harContent{Size: len(res.Tf_http_res_body), MimeType: res.Content_Type, Text: body}
+	e.Response.BodySize = len(res.Tf_http_res_body)
+	if !t.RequestAt.IsZero() && !t.ResponseAt.IsZero() {
+		// В логе есть только моменты отправки запроса и получения ответа
+		e.Time = float64(t.ResponseAt.Sub(t.RequestAt).Microseconds()) / 1000
+		e.Timings.Wait = e.Time
+	}
+	return e
+}
+
+func harHeaders(l *Log) []harNameValue {
+	headers := []harNameValue{}
+	v := reflect.ValueOf(l).Elem()
+	t := v.Type()
+	for _, i := range headerFields {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		f := v.Field(i)
+		// Повторяющийся заголовок (Vary) в логе — массив значений
+		if f.Kind() == reflect.Interface && !f.IsNil() {
+			if vals, ok := f.Interface().([]any); ok {
+				for _, val := range vals {
+					headers = append(headers, harNameValue{Name: name, Value: RedactHeader(name, fmt.Sprint(val))})
+				}
+				continue
+			}
+		}
+		if s, ok := valueString(f); ok {
+			headers = append(headers, harNameValue{Name: name, Value: RedactHeader(name, s)})
+		}
+	}
+	return headers
+}
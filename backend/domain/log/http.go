@@ -0,0 +1,63 @@
+package log
+
+import (
+	"sort"
+	"time"
+)
+
+// HTTPTransaction — пара запрос/ответ провайдера к API облака, связанная
+// по tf_http_trans_id. Request или Response может отсутствовать.
+type HTTPTransaction struct {
+	TransID    string    `json:"trans_id"`
+	TFReqID    string    `json:"tf_req_id,omitempty"`
+	RPC        string    `json:"rpc,omitempty"`
+	Method     string    `json:"method"`
+	URI        string    `json:"uri"`
+	Status     int       `json:"status,omitempty"`
+	RequestAt  time.Time `json:"request_at"`
+	ResponseAt time.Time `json:"response_at"`
+	DurationMs int       `json:"duration_ms"`
+	// Адреса ресурсов состояния, ID которых упоминает транзакция
+	Addresses []string `json:"addresses,omitempty"`
+
+	Request  *Log `json:"-"`
+	Response *Log `json:"-"`
+}
+
+// HTTPTransactions собирает транзакции записей в порядке отправки запросов.
+func HTTPTransactions(logs []Log) []HTTPTransaction {
+	byID := make(map[string]*HTTPTransaction)
+	var order []string
+	for i := range logs {
+		l := &logs[i]
+		if l.Tf_http_trans_id == "" || l.Tf_http_op_type == "" {
+			continue
+		}
+		t, ok := byID[l.Tf_http_trans_id]
+		if !ok {
+			t = &HTTPTransaction{TransID: l.Tf_http_trans_id, TFReqID: l.Tf_req_id, RPC: l.Tf_rpc}
+			byID[l.Tf_http_trans_id] = t
+			order = append(order, l.Tf_http_trans_id)
+		}
+		at, _ := Time(*l)
+		switch l.Tf_http_op_type {
+		case "request":
+			t.Request = l
+			t.Method, t.URI, t.RequestAt = l.Tf_http_req_method, l.Tf_http_req_uri, at
+			t.Addresses = l.StateAddresses
+		case "response":
+			t.Response = l
+			t.Status, t.ResponseAt = l.Tf_http_res_status_code, at
+		}
+	}
+	result := make([]HTTPTransaction, 0, len(order))
+	for _, id := range order {
+		t := byID[id]
+		if !t.RequestAt.IsZero() && !t.ResponseAt.IsZero() {
+			t.DurationMs = int(t.ResponseAt.Sub(t.RequestAt).Milliseconds())
+		}
+		result = append(result, *t)
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].RequestAt.Before(result[j].RequestAt) })
+	return result
+}
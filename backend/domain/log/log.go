@@ -2,7 +2,12 @@ package log
 
 import (
 	"bufio"
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"io"
 
 	"github.com/kaptinlin/jsonrepair"
@@ -89,25 +94,140 @@ type Log struct {
 	X_Runtime                                          string   `json:"X-Runtime,omitempty"`
 	Read                                               bool     `json:"read"`
 	Repaired                                           bool     `json:"repaired"`
+
+	State    TriageState `json:"state,omitempty"`
+	Assignee string      `json:"assignee,omitempty"`
+
+	Diag       *Diagnostic `json:"diag,omitempty"`
+	TemplateID string      `json:"template_id,omitempty"`
+	UploadID   string      `json:"upload_id,omitempty"`
+	LineNo     int         `json:"line_no,omitempty"`
+	ByteOffset int64       `json:"byte_offset,omitempty"`
+
+	// Метки, добавленные плагинами-обработчиками (например, имя проекта)
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// События terraform plan/apply -json (см. ui.go)
+	Type       string              `json:"type,omitempty"`
+	Hook       *UIHook             `json:"hook,omitempty"`
+	Change     *UIChange           `json:"change,omitempty"`
+	Changes    *UIChanges          `json:"changes,omitempty"`
+	Diagnostic *UIDiagnostic       `json:"diagnostic,omitempty"`
+	Terraform  string              `json:"terraform,omitempty"`
+	UI         string              `json:"ui,omitempty"`
+	Outputs    map[string]UIOutput `json:"outputs,omitempty"`
+
+	// Адреса ресурсов из загруженного состояния, ID которых упоминает
+	// HTTP-транзакция или диагностика (см. state.go)
+	StateAddresses []string `json:"state_addresses,omitempty"`
+
+	// Разобранные JSON-тела tf_http_req_body и tf_http_res_body для полей
+	// запроса req_body.* и res_body.* (см. body.go); в JSON записи не
+	// сохраняются и восстанавливаются ParseBodies
+	ReqBody any `json:"-"`
+	ResBody any `json:"-"`
 }
 
-func LoadLogs(r io.Reader) ([]Log, []string, error) {
-	var corruptedLogs []string
+// CorruptedLine — строка файла, которую не удалось разобрать как JSON.
+// LogID указывает на запись, полученную автоматическим восстановлением.
+type CorruptedLine struct {
+	ID           string `json:"id"`
+	UploadID     string `json:"upload_id"`
+	Line         int    `json:"line"`
+	Offset       int64  `json:"offset"`
+	Original     string `json:"original"`
+	ParseError   string `json:"parse_error"`
+	RepairedText string `json:"repaired_text,omitempty"`
+	RepairOK     bool   `json:"repair_ok"`
+	LogID        string `json:"log_id,omitempty"`
+	Resolved     bool   `json:"resolved"`
+}
+
+const maxLineSize = 16 << 20
+
+// LineID — детерминированный ID строки по её содержимому и номеру, чтобы
+// повторная загрузка того же файла давала те же записи.
+func LineID(raw []byte, lineNo int) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%d:", lineNo)
+	h.Write(raw)
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+func ParseLine(raw []byte) (Log, error) {
+	var log Log
+	if err := json.Unmarshal(raw, &log); err != nil {
+		return Log{}, err
+	}
+	if IsUIEvent(log) {
+		applyUIEvent(&log)
+	}
+	log.Diag = ParseDiagnostic(log)
+	log.ParseBodies()
+	return log, nil
+}
+
+// LoadLogs читает файл логов; формат (JSON или текст hclog) определяется
+// по началу файла.
+func LoadLogs(r io.Reader) ([]Log, []CorruptedLine, error) {
+	br := bufio.NewReaderSize(r, 64<<10)
+	head, _ := br.Peek(64 << 10)
+	format := DetectFormat(head)
+
+	var corruptedLogs []CorruptedLine
 	var logs []Log
-	scan := bufio.NewScanner(r)
+	var offset, lineStart int64
+	scan := bufio.NewScanner(br)
+	scan.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	scan.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		advance, token, err := bufio.ScanLines(data, atEOF)
+		if token != nil {
+			lineStart = offset
+		}
+		offset += int64(advance)
+		return advance, token, err
+	})
+	if format == FormatText {
+		return loadText(scan, &lineStart)
+	}
+	lineNo := 0
 	for scan.Scan() {
-		var log Log
+		lineNo++
 		raw := scan.Bytes()
-		if err := json.Unmarshal(raw, &log); err != nil {
-			corruptedLogs = append(corruptedLogs, string(raw))
+		if len(bytes.TrimSpace(raw)) == 0 {
+			continue
+		}
+		lineID := LineID(raw, lineNo)
+		log, err := ParseLine(raw)
+		if err != nil {
+			c := CorruptedLine{
+				ID:         lineID,
+				Line:       lineNo,
+				Offset:     lineStart,
+				Original:   string(raw),
+				ParseError: err.Error(),
+			}
 			fixed, err := jsonrepair.JSONRepair(string(raw))
-			if err != nil {
+			if err == nil {
+				c.RepairedText = fixed
+				log, err = ParseLine([]byte(fixed))
+				c.RepairOK = err == nil
+			}
+			if c.RepairOK {
+				log.Repaired = true
+				c.LogID = cmp.Or(log.Id, lineID)
+			}
+			corruptedLogs = append(corruptedLogs, c)
+			if !c.RepairOK {
 				continue
 			}
-			json.Unmarshal([]byte(fixed), &log)
-			log.Repaired = true
 		}
+		if log.Id == "" {
+			log.Id = lineID
+		}
+		log.LineNo = lineNo
+		log.ByteOffset = lineStart
 		logs = append(logs, log)
 	}
-	return logs, corruptedLogs, nil
+	return logs, corruptedLogs, scan.Err()
 }
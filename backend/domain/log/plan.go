@@ -0,0 +1,380 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Действия плана в виде одного слова: terraform пишет actions списком,
+// замена — ["delete","create"] или ["create","delete"].
+const (
+	PlanCreate  = "create"
+	PlanUpdate  = "update"
+	PlanDelete  = "delete"
+	PlanReplace = "replace"
+	PlanRead    = "read"
+	PlanNoOp    = "no-op"
+)
+
+// sensitiveValue заменяет значения, помеченные в плане как sensitive.
+const sensitiveValue = "(sensitive)"
+
+// Plan — план запуска из `terraform show -json plan.tfplan`, привязанный
+// к запуску (run_id) загрузок логов.
+type Plan struct {
+	RunID      string            `json:"run_id"`
+	FileName   string            `json:"file_name"`
+	UploadedAt time.Time         `json:"uploaded_at"`
+	Terraform  string            `json:"terraform_version,omitempty"`
+	Actions    map[string]int    `json:"actions"` // число ресурсов по действию
+	Resources  []PlannedResource `json:"resources"`
+}
+
+// PlannedResource — запланированное изменение ресурса.
+type PlannedResource struct {
+	Address      string   `json:"address"`
+	Mode         string   `json:"mode"` // managed, data
+	Type         string   `json:"type"`
+	Name         string   `json:"name"`
+	Provider     string   `json:"provider_name,omitempty"`
+	Actions      []string `json:"actions"`
+	Action       string   `json:"action"`
+	ActionReason string   `json:"action_reason,omitempty"`
+	Before       any      `json:"before"`
+	After        any      `json:"after"`
+	// Changed — пути атрибутов, которые меняются (в том числе на значения,
+	// известные только после apply); для create и delete пусто.
+	Changed []string `json:"changed"`
+	// AfterUnknown — атрибуты со значением "known after apply".
+	AfterUnknown []string `json:"after_unknown"`
+	ReplacePaths []string `json:"replace_paths,omitempty"`
+	Summary      string   `json:"summary"`
+}
+
+type planJSON struct {
+	FormatVersion    string             `json:"format_version"`
+	TerraformVersion string             `json:"terraform_version"`
+	PlannedValues    json.RawMessage    `json:"planned_values"`
+	ResourceChanges  []planResourceJSON `json:"resource_changes"`
+}
+
+type planResourceJSON struct {
+	Address      string `json:"address"`
+	Mode         string `json:"mode"`
+	Type         string `json:"type"`
+	Name         string `json:"name"`
+	ProviderName string `json:"provider_name"`
+	ActionReason string `json:"action_reason"`
+	Change       struct {
+		Actions         []string `json:"actions"`
+		Before          any      `json:"before"`
+		After           any      `json:"after"`
+		AfterUnknown    any      `json:"after_unknown"`
+		BeforeSensitive any      `json:"before_sensitive"`
+		AfterSensitive  any      `json:"after_sensitive"`
+		ReplacePaths    [][]any  `json:"replace_paths"`
+	} `json:"change"`
+}
+
+// ParsePlan разбирает JSON-представление плана. Файлы без format_version
+// или без planned_values/resource_changes (например, состояние) — ErrInvalid.
+func ParsePlan(data []byte) (Plan, error) {
+	var raw planJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return Plan{}, fmt.Errorf("plan: %v: %w", err, ErrInvalid)
+	}
+	if raw.FormatVersion == "" || raw.PlannedValues == nil && raw.ResourceChanges == nil {
+		return Plan{}, fmt.Errorf("not a terraform show -json plan: %w", ErrInvalid)
+	}
+	plan := Plan{
+		Terraform: raw.TerraformVersion,
+		Actions:   make(map[string]int),
+		Resources: make([]PlannedResource, 0, len(raw.ResourceChanges)),
+	}
+	for _, rc := range raw.ResourceChanges {
+		res := plannedResource(rc)
+		plan.Actions[res.Action]++
+		plan.Resources = append(plan.Resources, res)
+	}
+	sort.Slice(plan.Resources, func(i, j int) bool {
+		return plan.Resources[i].Address < plan.Resources[j].Address
+	})
+	return plan, nil
+}
+
+func plannedResource(rc planResourceJSON) PlannedResource {
+	c := rc.Change
+	res := PlannedResource{
+		Address:      rc.Address,
+		Mode:         rc.Mode,
+		Type:         rc.Type,
+		Name:         rc.Name,
+		Provider:     rc.ProviderName,
+		Actions:      c.Actions,
+		Action:       planAction(c.Actions),
+		ActionReason: rc.ActionReason,
+		Changed:      []string{},
+		AfterUnknown: []string{},
+	}
+	if res.Actions == nil {
+		res.Actions = []string{}
+	}
+	unknownPaths(c.AfterUnknown, "", &res.AfterUnknown)
+	if res.Action == PlanUpdate || res.Action == PlanReplace {
+		changedPaths(c.Before, c.After, c.AfterUnknown, "", &res.Changed)
+	}
+	// Маскировка меняет значения на месте, поэтому после сравнения
+	res.Before = maskSensitive(c.Before, c.BeforeSensitive)
+	res.After = maskSensitive(c.After, c.AfterSensitive)
+	for _, p := range c.ReplacePaths {
+		res.ReplacePaths = append(res.ReplacePaths, pathString(p))
+	}
+	res.Summary = planSummary(res)
+	return res
+}
+
+func planAction(actions []string) string {
+	switch {
+	case len(actions) == 2 && slices.Contains(actions, "delete") && slices.Contains(actions, "create"):
+		return PlanReplace
+	case len(actions) == 1:
+		return actions[0]
+	case len(actions) == 0:
+		return PlanNoOp
+	}
+	return strings.Join(actions, ",")
+}
+
+// planSummary — описание изменения для человека:
+// "an update of t1_vpc_vip.foo changing network_interface_ids".
+func planSummary(res PlannedResource) string {
+	var s string
+	switch res.Action {
+	case PlanNoOp:
+		return "no changes to " + res.Address
+	case PlanCreate:
+		s = "creation of " + res.Address
+	case PlanUpdate:
+		s = "an update of " + res.Address
+	case PlanDelete:
+		s = "deletion of " + res.Address
+	case PlanReplace:
+		s = "replacement of " + res.Address
+	case PlanRead:
+		s = "a read of " + res.Address
+	default:
+		s = res.Action + " of " + res.Address
+	}
+	if len(res.Changed) > 0 {
+		s += " changing " + strings.Join(res.Changed, ", ")
+	}
+	if len(res.ReplacePaths) > 0 {
+		s += " (replacement forced by " + strings.Join(res.ReplacePaths, ", ") + ")"
+	}
+	return s
+}
+
+// maskSensitive заменяет значения, отмеченные в *_sensitive, на
+// "(sensitive)". Разметка повторяет структуру значения.
+func maskSensitive(value, sensitive any) any {
+	switch s := sensitive.(type) {
+	case bool:
+		if s && value != nil {
+			return sensitiveValue
+		}
+	case map[string]any:
+		if v, ok := value.(map[string]any); ok {
+			for k, sub := range s {
+				if _, ok := v[k]; ok {
+					v[k] = maskSensitive(v[k], sub)
+				}
+			}
+		}
+	case []any:
+		if v, ok := value.([]any); ok {
+			for i := range min(len(s), len(v)) {
+				v[i] = maskSensitive(v[i], s[i])
+			}
+		}
+	}
+	return value
+}
+
+// changedPaths собирает пути различающихся атрибутов. Вложенные объекты и
+// списки одинаковой длины сравниваются поэлементно, атрибут с неизвестным
+// после apply значением считается изменённым, если до него было значение.
+func changedPaths(before, after, unknown any, path string, out *[]string) {
+	if u, ok := unknown.(bool); ok && u {
+		if before != nil {
+			*out = append(*out, path)
+		}
+		return
+	}
+	switch b := before.(type) {
+	case map[string]any:
+		a, ok := after.(map[string]any)
+		if !ok {
+			break
+		}
+		u, _ := unknown.(map[string]any)
+		keys := make(map[string]bool)
+		for k := range b {
+			keys[k] = true
+		}
+		for k := range a {
+			keys[k] = true
+		}
+		for _, k := range slices.Sorted(maps.Keys(keys)) {
+			changedPaths(b[k], a[k], u[k], joinPath(path, k), out)
+		}
+		return
+	case []any:
+		a, ok := after.([]any)
+		if !ok || len(a) != len(b) || path == "" {
+			break
+		}
+		u, _ := unknown.([]any)
+		for i := range b {
+			var sub any
+			if i < len(u) {
+				sub = u[i]
+			}
+			changedPaths(b[i], a[i], sub, path+"["+strconv.Itoa(i)+"]", out)
+		}
+		return
+	}
+	if path != "" && !reflect.DeepEqual(before, after) {
+		*out = append(*out, path)
+	}
+}
+
+// unknownPaths собирает пути, отмеченные true в after_unknown.
+func unknownPaths(unknown any, path string, out *[]string) {
+	switch u := unknown.(type) {
+	case bool:
+		if u && path != "" {
+			*out = append(*out, path)
+		}
+	case map[string]any:
+		for _, k := range slices.Sorted(maps.Keys(u)) {
+			unknownPaths(u[k], joinPath(path, k), out)
+		}
+	case []any:
+		for i, sub := range u {
+			unknownPaths(sub, path+"["+strconv.Itoa(i)+"]", out)
+		}
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// pathString переводит путь replace_paths (["rules", 0, "port"]) в rules[0].port.
+func pathString(steps []any) string {
+	var path string
+	for _, step := range steps {
+		switch s := step.(type) {
+		case string:
+			path = joinPath(path, s)
+		case float64:
+			path += "[" + strconv.Itoa(int(s)) + "]"
+		}
+	}
+	return path
+}
+
+// ResourceChanges — изменения плана для ресурса вызова провайдера: полный
+// адрес, адрес без модуля или только тип (см. ResourceAddress).
+func (p Plan) ResourceChanges(resource string) []PlannedResource {
+	if resource == "" {
+		return nil
+	}
+	var res []PlannedResource
+	for _, rc := range p.Resources {
+		typ := rc.Type
+		if rc.Mode == "data" {
+			typ = "data." + typ
+		}
+		if resource == rc.Address || strings.HasSuffix(rc.Address, "."+resource) || resource == typ {
+			res = append(res, rc)
+		}
+	}
+	return res
+}
+
+// PlanResourceReport — изменение плана и вызовы провайдера по ресурсу из
+// загрузок того же запуска.
+type PlanResourceReport struct {
+	PlannedResource
+	RPCs []RPCCall `json:"rpcs"`
+}
+
+// PlanReport — план запуска, сопоставленный с TF_LOG его загрузок.
+type PlanReport struct {
+	RunID      string               `json:"run_id"`
+	FileName   string               `json:"file_name"`
+	UploadedAt time.Time            `json:"uploaded_at"`
+	Terraform  string               `json:"terraform_version,omitempty"`
+	Actions    map[string]int       `json:"actions"`
+	Uploads    []string             `json:"uploads"`
+	Resources  []PlanResourceReport `json:"resources"`
+}
+
+// CorrelatePlan распределяет вызовы провайдера по ресурсам плана. Вызов,
+// ресурс которого известен только по типу, а ресурсов этого типа в плане
+// несколько, не привязывается.
+func CorrelatePlan(plan Plan, trace []Log) PlanReport {
+	report := PlanReport{
+		RunID:      plan.RunID,
+		FileName:   plan.FileName,
+		UploadedAt: plan.UploadedAt,
+		Terraform:  plan.Terraform,
+		Actions:    plan.Actions,
+		Uploads:    []string{},
+		Resources:  make([]PlanResourceReport, 0, len(plan.Resources)),
+	}
+	idx := make(map[string]int, len(plan.Resources))
+	for i, rc := range plan.Resources {
+		idx[rc.Address] = i
+		report.Resources = append(report.Resources, PlanResourceReport{PlannedResource: rc, RPCs: []RPCCall{}})
+	}
+	for _, call := range RPCCalls(trace) {
+		if call.RPC == "GetProviderSchema" {
+			continue
+		}
+		if changes := plan.ResourceChanges(call.Resource); len(changes) == 1 {
+			res := &report.Resources[idx[changes[0].Address]]
+			res.RPCs = append(res.RPCs, call)
+		}
+	}
+	return report
+}
+
+// GroupPlan — изменение плана, ради которого выполнялся вызов провайдера.
+type GroupPlan struct {
+	RunID    string            `json:"run_id,omitempty"`
+	RPC      string            `json:"rpc,omitempty"`
+	Resource string            `json:"resource,omitempty"`
+	Changes  []PlannedResource `json:"changes"`
+	// Note — "PlanResourceChange was for an update of t1_vpc_vip.foo
+	// changing network_interface_ids", если изменение одно.
+	Note string `json:"note,omitempty"`
+}
+
+// Group — записи одного вызова (tf_req_id) и изменение плана по ресурсу.
+type Group struct {
+	TFReqID string    `json:"tf_req_id"`
+	Items   []Log     `json:"items"`
+	Plan    GroupPlan `json:"plan"`
+}
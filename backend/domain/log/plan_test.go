@@ -0,0 +1,208 @@
+package log
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func decodeJSON(t *testing.T, s string) any {
+	t.Helper()
+	if s == "" {
+		return nil
+	}
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatalf("%s: %v", s, err)
+	}
+	return v
+}
+
+func encodeJSON(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestMaskSensitive(t *testing.T) {
+	tests := []struct {
+		name, value, sensitive, want string
+	}{
+		{"scalar", `"s3cr3t"`, `true`, `"(sensitive)"`},
+		{"not sensitive", `"x"`, `false`, `"x"`},
+		{"null stays null", `{"password":null}`, `{"password":true}`, `{"password":null}`},
+		{"nested key", `{"auth":{"user":"u","password":"p"}}`, `{"auth":{"password":true}}`, `{"auth":{"password":"(sensitive)","user":"u"}}`},
+		{"whole object", `{"auth":{"user":"u"}}`, `{"auth":true}`, `{"auth":"(sensitive)"}`},
+		{"list element", `{"keys":["a","b"]}`, `{"keys":[false,true]}`, `{"keys":["a","(sensitive)"]}`},
+		{"list of objects", `{"rules":[{"port":22,"secret":"s"}]}`, `{"rules":[{"secret":true}]}`, `{"rules":[{"port":22,"secret":"(sensitive)"}]}`},
+		{"marking longer than list", `{"keys":["a"]}`, `{"keys":[true,true]}`, `{"keys":["(sensitive)"]}`},
+		{"marking of missing key", `{"a":1}`, `{"b":true}`, `{"a":1}`},
+		{"shape mismatch", `"x"`, `{"a":true}`, `"x"`},
+	}
+	for _, tt := range tests {
+		got := encodeJSON(t, maskSensitive(decodeJSON(t, tt.value), decodeJSON(t, tt.sensitive)))
+		if got != tt.want {
+			t.Errorf("%s: maskSensitive = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestChangedPaths(t *testing.T) {
+	tests := []struct {
+		name, before, after, unknown, want string
+	}{
+		{"equal", `{"a":1,"b":{"c":2}}`, `{"a":1,"b":{"c":2}}`, ``, ""},
+		{"scalar", `{"a":1}`, `{"a":2}`, ``, "a"},
+		{"nested", `{"b":{"c":2,"d":3}}`, `{"b":{"c":2,"d":4}}`, ``, "b.d"},
+		{"added and removed keys", `{"a":1}`, `{"b":1}`, ``, "a,b"},
+		{"list element", `{"l":[1,2]}`, `{"l":[1,3]}`, ``, "l[1]"},
+		{"list of objects", `{"l":[{"p":1},{"p":2}]}`, `{"l":[{"p":1},{"p":5}]}`, ``, "l[1].p"},
+		{"list length", `{"l":[1]}`, `{"l":[1,2]}`, ``, "l"},
+		{"unknown with old value", `{"ip":"10.0.0.1"}`, `{}`, `{"ip":true}`, "ip"},
+		{"unknown without old value", `{"id":null}`, `{}`, `{"id":true}`, ""},
+		{"unknown list element", `{"l":["x","y"]}`, `{"l":["x",null]}`, `{"l":[false,true]}`, "l[1]"},
+		{"unknown list element equal length only", `{"l":["x"]}`, `{"l":["x",null]}`, `{"l":[false,true]}`, "l"},
+	}
+	for _, tt := range tests {
+		var got []string
+		changedPaths(decodeJSON(t, tt.before), decodeJSON(t, tt.after), decodeJSON(t, tt.unknown), "", &got)
+		if strings.Join(got, ",") != tt.want {
+			t.Errorf("%s: changedPaths = %v, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+const testPlan = `{
+  "format_version": "1.2",
+  "terraform_version": "1.13.1",
+  "planned_values": {},
+  "resource_changes": [
+    {
+      "address": "t1_vpc_vip.office",
+      "mode": "managed", "type": "t1_vpc_vip", "name": "office",
+      "change": {
+        "actions": ["update"],
+        "before": {"name": "office", "auth": {"user": "u", "password": "old"}, "ports": [80, 443]},
+        "after": {"name": "office-2", "auth": {"user": "u", "password": "new"}, "ports": [80, 8443]},
+        "after_unknown": {},
+        "before_sensitive": {"auth": {"password": true}},
+        "after_sensitive": {"auth": {"password": true}}
+      }
+    },
+    {
+      "address": "t1_vpc_network.main",
+      "mode": "managed", "type": "t1_vpc_network", "name": "main",
+      "action_reason": "replace_because_cannot_update",
+      "change": {
+        "actions": ["delete", "create"],
+        "before": {"id": "n-1", "cidr": "10.0.0.0/24", "tags": ["a", "b"]},
+        "after": {"cidr": "10.1.0.0/24", "tags": ["a", null]},
+        "after_unknown": {"id": true, "tags": [false, true]},
+        "before_sensitive": {},
+        "after_sensitive": {},
+        "replace_paths": [["cidr"], ["rules", 0, "port"]]
+      }
+    },
+    {
+      "address": "module.net.t1_vpc_subnet.s",
+      "mode": "managed", "type": "t1_vpc_subnet", "name": "s",
+      "change": {
+        "actions": ["create"],
+        "before": null,
+        "after": {"name": "s", "ips": [null, "10.0.0.5"], "token": "t"},
+        "after_unknown": {"id": true, "ips": [true, false]},
+        "before_sensitive": false,
+        "after_sensitive": {"token": true}
+      }
+    }
+  ]
+}`
+
+func TestParsePlan(t *testing.T) {
+	plan, err := ParsePlan([]byte(testPlan))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Terraform != "1.13.1" || plan.Actions[PlanUpdate] != 1 || plan.Actions[PlanReplace] != 1 || plan.Actions[PlanCreate] != 1 {
+		t.Fatalf("plan = %s %v", plan.Terraform, plan.Actions)
+	}
+	byAddr := make(map[string]PlannedResource)
+	for _, r := range plan.Resources {
+		byAddr[r.Address] = r
+	}
+
+	update := byAddr["t1_vpc_vip.office"]
+	if got := strings.Join(update.Changed, ","); got != "auth.password,name,ports[1]" {
+		t.Errorf("update changed = %s", got)
+	}
+	if got := encodeJSON(t, update.Before); got != `{"auth":{"password":"(sensitive)","user":"u"},"name":"office","ports":[80,443]}` {
+		t.Errorf("update before = %s", got)
+	}
+	if got := encodeJSON(t, update.After); strings.Contains(got, "new") {
+		t.Errorf("update after leaks a sensitive value: %s", got)
+	}
+
+	replace := byAddr["t1_vpc_network.main"]
+	if replace.Action != PlanReplace {
+		t.Errorf("action = %s, want replace", replace.Action)
+	}
+	if got := strings.Join(replace.Changed, ","); got != "cidr,id,tags[1]" {
+		t.Errorf("replace changed = %s", got)
+	}
+	if got := strings.Join(replace.AfterUnknown, ","); got != "id,tags[1]" {
+		t.Errorf("replace after_unknown = %s", got)
+	}
+	if got := strings.Join(replace.ReplacePaths, ","); got != "cidr,rules[0].port" {
+		t.Errorf("replace_paths = %s", got)
+	}
+	want := "replacement of t1_vpc_network.main changing cidr, id, tags[1] (replacement forced by cidr, rules[0].port)"
+	if replace.Summary != want {
+		t.Errorf("summary = %q, want %q", replace.Summary, want)
+	}
+
+	create := byAddr["module.net.t1_vpc_subnet.s"]
+	if len(create.Changed) != 0 {
+		t.Errorf("create changed = %v, want none", create.Changed)
+	}
+	if got := strings.Join(create.AfterUnknown, ","); got != "id,ips[0]" {
+		t.Errorf("create after_unknown = %s", got)
+	}
+	if got := encodeJSON(t, create.After); got != `{"ips":[null,"10.0.0.5"],"name":"s","token":"(sensitive)"}` {
+		t.Errorf("create after = %s", got)
+	}
+	if got := plan.ResourceChanges("t1_vpc_subnet.s"); len(got) != 1 || got[0].Address != create.Address {
+		t.Errorf("ResourceChanges without module = %v", got)
+	}
+}
+
+func TestParsePlanInvalid(t *testing.T) {
+	for _, data := range []string{`{}`, `{"format_version":"1.2"}`, `not json`, `{"version":4,"resources":[]}`} {
+		if _, err := ParsePlan([]byte(data)); !errors.Is(err, ErrInvalid) {
+			t.Errorf("ParsePlan(%s) = %v, want ErrInvalid", data, err)
+		}
+	}
+}
+
+func TestPlanAction(t *testing.T) {
+	tests := []struct {
+		actions []string
+		want    string
+	}{
+		{nil, PlanNoOp},
+		{[]string{"no-op"}, PlanNoOp},
+		{[]string{"create", "delete"}, PlanReplace},
+		{[]string{"delete", "create"}, PlanReplace},
+		{[]string{"read"}, PlanRead},
+		{[]string{"update", "read"}, "update,read"},
+	}
+	for _, tt := range tests {
+		if got := planAction(slices.Clone(tt.actions)); got != tt.want {
+			t.Errorf("planAction(%v) = %s, want %s", tt.actions, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package log
+
+import "context"
+
+// Processor — расширение обработки загрузок (плагины, см. пакет plugin).
+type Processor interface {
+	// ProcessEntries вызывается до сохранения записей загрузки и
+	// возвращает их в том же количестве и порядке.
+	ProcessEntries(ctx context.Context, logs []Log) []Log
+	// UploadComplete вызывается под блокировкой хранилища и не должен ждать.
+	UploadComplete(ctx context.Context, ev UploadComplete)
+}
+
+// UploadComplete — сводка завершённой загрузки для обработчиков.
+type UploadComplete struct {
+	Workspace string     `json:"workspace"`
+	Upload    Upload     `json:"upload"`
+	Summary   RunSummary `json:"summary"`
+}
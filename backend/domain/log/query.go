@@ -0,0 +1,503 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Язык запросов для фильтрации записей:
+//
+//	tf_rpc = ApplyResourceChange AND (severity = error OR tf_http_res_status_code >= 500)
+//	@message ~ "timeout|deadline" NOT tf_resource_type = t1_vpc_vip
+//	diag.code exists "quota"
+//	res_body.error.code = "items_compute_find_service_error" OR req_body.name exists
+//
+// Условия: поле (тег JSON записи, производное поле, labels.<метка> или путь в
+// JSON-теле req_body.<путь> / res_body.<путь>), оператор = != ~ !~ > >= < <=
+// и значение; "поле exists"; отдельное слово или строка в кавычках — поиск
+// подстроки по всей записи. Соседние условия объединяются через AND.
+type Query struct {
+	src  string
+	root queryNode
+}
+
+type queryNode interface {
+	match(e *queryEval) bool
+}
+
+// queryEval — вычисление запроса для одной записи; JSON записи для
+// полнотекстового поиска строится один раз.
+type queryEval struct {
+	l    *Log
+	text []byte
+}
+
+func (e *queryEval) lowerJSON() []byte {
+	if e.text == nil {
+		b, _ := json.Marshal(e.l)
+		e.text = bytes.ToLower(b)
+	}
+	return e.text
+}
+
+type fieldGetter func(l *Log) (string, bool)
+
+var (
+	logFields     = make(map[string]fieldGetter) // тег JSON в нижнем регистре -> значение
+	derivedFields = map[string]fieldGetter{
+		"severity":          nonEmpty(func(l *Log) string { return Severity(*l) }),
+		"resource":          nonEmpty(func(l *Log) string { return ResourceType(*l) }),
+		"message":           nonEmpty(func(l *Log) string { return l.At_message }),
+		"module":            nonEmpty(func(l *Log) string { return l.At_module }),
+		"state":             nonEmpty(func(l *Log) string { return string(l.TriageState()) }),
+		"uri_template":      nonEmpty(func(l *Log) string { return uriTemplate(l) }),
+		"diag.code":         diagField(func(d *Diagnostic) string { return d.ErrorCode }),
+		"diag.attribute":    diagField(func(d *Diagnostic) string { return d.AttributePath }),
+		"diag.request_id":   diagField(func(d *Diagnostic) string { return d.RequestID }),
+		"diag.http_status":  diagField(func(d *Diagnostic) string { return intString(d.HTTPStatus) }),
+		"diag.status_text":  diagField(func(d *Diagnostic) string { return d.HTTPStatusText }),
+		"diag.request_time": diagField(func(d *Diagnostic) string { return d.RequestTime }),
+		"ui.address":        nonEmpty(func(l *Log) string { return UIAddress(*l) }),
+		"ui.action":         nonEmpty(uiAction),
+		"ui.elapsed": func(l *Log) (string, bool) {
+			if l.Hook == nil || l.Hook.ElapsedSeconds == 0 {
+				return "", false
+			}
+			return strconv.FormatFloat(l.Hook.ElapsedSeconds, 'f', -1, 64), true
+		},
+	}
+)
+
+func init() {
+	t := reflect.TypeFor[Log]()
+	for i := range t.NumField() {
+		tag, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		idx := i
+		logFields[strings.ToLower(tag)] = func(l *Log) (string, bool) {
+			return valueString(reflect.ValueOf(l).Elem().Field(idx))
+		}
+	}
+}
+
+func nonEmpty(fn func(l *Log) string) fieldGetter {
+	return func(l *Log) (string, bool) {
+		v := fn(l)
+		return v, v != ""
+	}
+}
+
+func diagField(fn func(d *Diagnostic) string) fieldGetter {
+	return func(l *Log) (string, bool) {
+		if l.Diag == nil {
+			return "", false
+		}
+		v := fn(l.Diag)
+		return v, v != ""
+	}
+}
+
+func intString(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+func uriTemplate(l *Log) string {
+	if l.Tf_http_req_uri == "" {
+		return ""
+	}
+	return NormalizeURI(l.Tf_http_req_uri)
+}
+
+func valueString(v reflect.Value) (string, bool) {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), v.String() != ""
+	case reflect.Int, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), v.Int() != 0
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), v.Bool()
+	case reflect.Slice:
+		parts := make([]string, v.Len())
+		for i := range parts {
+			parts[i] = fmt.Sprint(v.Index(i).Interface())
+		}
+		return strings.Join(parts, ","), v.Len() > 0
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			return "", false
+		}
+		if s, ok := v.Interface().(fmt.Stringer); ok {
+			return s.String(), true
+		}
+		b, _ := json.Marshal(v.Interface())
+		return strings.Trim(string(b), `"`), true
+	}
+	return fmt.Sprint(v.Interface()), !v.IsZero()
+}
+
+// QueryFields перечисляет производные поля запроса (помимо тегов JSON записи).
+func QueryFields() []string {
+	fields := make([]string, 0, len(derivedFields))
+	for name := range derivedFields {
+		fields = append(fields, name)
+	}
+	return fields
+}
+
+func lookupField(name string) (fieldGetter, bool) {
+	if key, ok := strings.CutPrefix(name, "labels."); ok && key != "" {
+		return func(l *Log) (string, bool) {
+			v, ok := l.Labels[key]
+			return v, ok
+		}, true
+	}
+	if path, ok := bodyPath(name, reqBodyField); ok {
+		return bodyField(func(l *Log) any { return l.ReqBody }, path), true
+	}
+	if path, ok := bodyPath(name, resBodyField); ok {
+		return bodyField(func(l *Log) any { return l.ResBody }, path), true
+	}
+	name = strings.ToLower(name)
+	if g, ok := derivedFields[name]; ok {
+		return g, true
+	}
+	g, ok := logFields[name]
+	return g, ok
+}
+
+// FieldValue возвращает функцию чтения поля запроса по имени, например
+// для колонок экспорта; незаполненное поле даёт пустую строку.
+func FieldValue(name string) (func(l *Log) string, error) {
+	g, ok := lookupField(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown field %q", name)
+	}
+	return func(l *Log) string {
+		if v, ok := g(l); ok {
+			return v
+		}
+		return ""
+	}, nil
+}
+
+// ParseQuery разбирает запрос; пустая строка соответствует любой записи.
+func ParseQuery(src string) (*Query, error) {
+	p := &queryParser{src: src}
+	if err := p.scan(); err != nil {
+		return nil, err
+	}
+	q := &Query{src: src}
+	if len(p.tokens) == 0 {
+		return q, nil
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.errorf(t, "unexpected %q", t.text)
+	}
+	q.root = root
+	return q, nil
+}
+
+func (q *Query) String() string {
+	return q.src
+}
+
+func (q *Query) Match(l *Log) bool {
+	if q == nil || q.root == nil {
+		return true
+	}
+	return q.root.match(&queryEval{l: l})
+}
+
+type andNode struct{ left, right queryNode }
+type orNode struct{ left, right queryNode }
+type notNode struct{ node queryNode }
+
+func (n andNode) match(e *queryEval) bool { return n.left.match(e) && n.right.match(e) }
+func (n orNode) match(e *queryEval) bool  { return n.left.match(e) || n.right.match(e) }
+func (n notNode) match(e *queryEval) bool { return !n.node.match(e) }
+
+type existsNode struct{ get fieldGetter }
+
+func (n existsNode) match(e *queryEval) bool {
+	_, ok := n.get(e.l)
+	return ok
+}
+
+type textNode struct{ text []byte }
+
+func (n textNode) match(e *queryEval) bool {
+	return bytes.Contains(e.lowerJSON(), n.text)
+}
+
+type compareNode struct {
+	get   fieldGetter
+	op    string
+	value string
+	num   float64
+	isNum bool
+	re    *regexp.Regexp
+}
+
+func (n compareNode) match(e *queryEval) bool {
+	v, ok := n.get(e.l)
+	switch n.op {
+	case "=":
+		return v == n.value
+	case "!=":
+		return v != n.value
+	case "~":
+		return n.re.MatchString(v)
+	case "!~":
+		return !n.re.MatchString(v)
+	}
+	// Порядковые сравнения: числа — как числа, остальное — как строки
+	// (временные метки одного формата сравниваются корректно). Отсутствующее
+	// поле не сравнивается: иначе "status < 400" совпало бы с записями без HTTP
+	if !ok {
+		return false
+	}
+	var cmp int
+	if f, err := strconv.ParseFloat(v, 64); err == nil && n.isNum {
+		switch {
+		case f < n.num:
+			cmp = -1
+		case f > n.num:
+			cmp = 1
+		}
+	} else {
+		cmp = strings.Compare(v, n.value)
+	}
+	switch n.op {
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+	return false
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+type queryParser struct {
+	src    string
+	tokens []token
+	i      int
+}
+
+func (p *queryParser) errorf(t token, format string, args ...any) error {
+	return fmt.Errorf("query: %s at position %d", fmt.Sprintf(format, args...), t.pos+1)
+}
+
+func isOpChar(r byte) bool {
+	return r == '=' || r == '!' || r == '~' || r == '<' || r == '>'
+}
+
+func (p *queryParser) scan() error {
+	s := p.src
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case unicode.IsSpace(rune(c)):
+			i++
+		case c == '(':
+			p.tokens = append(p.tokens, token{tokLParen, "(", i})
+			i++
+		case c == ')':
+			p.tokens = append(p.tokens, token{tokRParen, ")", i})
+			i++
+		case c == '"':
+			j := i + 1
+			for j < len(s) && s[j] != '"' {
+				if s[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(s) {
+				return p.errorf(token{pos: i}, "unterminated string")
+			}
+			text, err := strconv.Unquote(s[i : j+1])
+			if err != nil {
+				return p.errorf(token{pos: i}, "invalid string: %v", err)
+			}
+			p.tokens = append(p.tokens, token{tokString, text, i})
+			i = j + 1
+		case isOpChar(c):
+			j := i
+			for j < len(s) && isOpChar(s[j]) {
+				j++
+			}
+			op := s[i:j]
+			switch op {
+			case "=", "==", "!=", "~", "!~", ">", ">=", "<", "<=":
+			default:
+				return p.errorf(token{pos: i}, "unknown operator %q", op)
+			}
+			if op == "==" {
+				op = "="
+			}
+			p.tokens = append(p.tokens, token{tokOp, op, i})
+			i = j
+		default:
+			j := i
+			for j < len(s) && !unicode.IsSpace(rune(s[j])) && s[j] != '(' && s[j] != ')' && s[j] != '"' && !isOpChar(s[j]) {
+				j++
+			}
+			p.tokens = append(p.tokens, token{tokWord, s[i:j], i})
+			i = j
+		}
+	}
+	return nil
+}
+
+func (p *queryParser) peek() token {
+	if p.i < len(p.tokens) {
+		return p.tokens[p.i]
+	}
+	return token{kind: tokEOF, pos: len(p.src)}
+}
+
+func (p *queryParser) next() token {
+	t := p.peek()
+	if p.i < len(p.tokens) {
+		p.i++
+	}
+	return t
+}
+
+func isKeyword(t token, kw string) bool {
+	return t.kind == tokWord && strings.EqualFold(t.text, kw)
+}
+
+func (p *queryParser) parseOr() (queryNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for isKeyword(p.peek(), "OR") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAnd() (queryNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if t.kind == tokEOF || t.kind == tokRParen || isKeyword(t, "OR") {
+			return left, nil
+		}
+		if isKeyword(t, "AND") {
+			p.next()
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+}
+
+func (p *queryParser) parseUnary() (queryNode, error) {
+	t := p.next()
+	switch {
+	case t.kind == tokEOF:
+		return nil, p.errorf(t, "unexpected end of query")
+	case isKeyword(t, "NOT"):
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{n}, nil
+	case t.kind == tokLParen:
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if r := p.next(); r.kind != tokRParen {
+			return nil, p.errorf(r, "expected \")\"")
+		}
+		return n, nil
+	case t.kind == tokString:
+		return textNode{bytes.ToLower([]byte(t.text))}, nil
+	case t.kind == tokWord:
+		if isKeyword(t, "AND") || isKeyword(t, "OR") {
+			return nil, p.errorf(t, "unexpected %s", strings.ToUpper(t.text))
+		}
+		next := p.peek()
+		if next.kind == tokOp || isKeyword(next, "exists") {
+			return p.parseCondition(t)
+		}
+		return textNode{bytes.ToLower([]byte(t.text))}, nil
+	}
+	return nil, p.errorf(t, "unexpected %q", t.text)
+}
+
+func (p *queryParser) parseCondition(field token) (queryNode, error) {
+	get, ok := lookupField(field.text)
+	if !ok {
+		return nil, p.errorf(field, "unknown field %q", field.text)
+	}
+	op := p.next()
+	if isKeyword(op, "exists") {
+		return existsNode{get}, nil
+	}
+	val := p.next()
+	if val.kind != tokWord && val.kind != tokString {
+		return nil, p.errorf(val, "expected value after %s", op.text)
+	}
+	n := compareNode{get: get, op: op.text, value: val.text}
+	if f, err := strconv.ParseFloat(val.text, 64); err == nil {
+		n.num, n.isNum = f, true
+	}
+	if n.op == "~" || n.op == "!~" {
+		re, err := regexp.Compile(val.text)
+		if err != nil {
+			return nil, p.errorf(val, "invalid regexp: %v", err)
+		}
+		n.re = re
+	}
+	return n, nil
+}
@@ -0,0 +1,170 @@
+package log
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// queryLogs — записи для проверки запросов, ID совпадает с буквой.
+func queryLogs() []Log {
+	logs := []Log{
+		{
+			Id:                      "a",
+			At_level:                "error",
+			At_message:              "request timeout",
+			Tf_rpc:                  "ApplyResourceChange",
+			Tf_resource_type:        "t1_vpc_vip",
+			Tf_http_res_status_code: 500,
+			Tf_http_res_body:        `{"error":{"code":"quota_exceeded"}}`,
+		},
+		{
+			Id:                      "b",
+			At_level:                "debug",
+			At_message:              "Received HTTP Response",
+			Tf_rpc:                  "ReadResource",
+			Tf_resource_type:        "t1_vpc_network",
+			Tf_http_res_status_code: 200,
+			Tf_http_res_body:        `[{"id":"n-1","name":"office"},{"id":"n-2"}]`,
+		},
+		{
+			Id:                      "c",
+			At_level:                "warn",
+			At_message:              "deadline exceeded",
+			Tf_rpc:                  "ApplyResourceChange",
+			Tf_resource_type:        "t1_vpc_network",
+			Tf_http_res_status_code: 90,
+			Labels:                  map[string]string{"project": "billing"},
+		},
+		{
+			Id:         "d",
+			At_level:   "info",
+			At_message: "Starting provider",
+			Timestamp:  "2025-09-09T15:31:33.022+0300",
+		},
+	}
+	for i := range logs {
+		logs[i].ParseBodies()
+	}
+	return logs
+}
+
+func matchIDs(t *testing.T, src string) string {
+	t.Helper()
+	q, err := ParseQuery(src)
+	if err != nil {
+		t.Fatalf("ParseQuery(%q): %v", src, err)
+	}
+	var ids []string
+	for _, l := range queryLogs() {
+		if q.Match(&l) {
+			ids = append(ids, l.Id)
+		}
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestQueryMatch(t *testing.T) {
+	tests := []struct {
+		name, query, want string
+	}{
+		{"empty matches all", "", "a,b,c,d"},
+		{"blank matches all", "   ", "a,b,c,d"},
+		{"equals", "tf_rpc = ApplyResourceChange", "a,c"},
+		{"double equals", "tf_rpc == ReadResource", "b"},
+		{"not equals includes missing", "tf_rpc != ApplyResourceChange", "b,d"},
+		{"field name case insensitive", "TF_RPC = ReadResource", "b"},
+		{"regexp", `@message ~ "timeout|deadline"`, "a,c"},
+		{"negated regexp", `@message !~ "timeout|deadline"`, "b,d"},
+
+		{"implicit AND", "tf_rpc = ApplyResourceChange severity = error", "a"},
+		{"explicit AND", "tf_rpc = ApplyResourceChange AND severity = warning", "c"},
+		{"keywords case insensitive", "tf_rpc = ReadResource or severity = warning", "b,c"},
+		{"AND binds tighter than OR", "tf_rpc = ReadResource OR tf_rpc = ApplyResourceChange AND severity = error", "a,b"},
+		{"parentheses", "(tf_rpc = ReadResource OR tf_rpc = ApplyResourceChange) AND severity = error", "a"},
+		{"NOT", "NOT tf_rpc = ApplyResourceChange", "b,d"},
+		{"NOT binds to next term", "NOT tf_rpc = ApplyResourceChange AND tf_resource_type exists", "b"},
+		{"NOT group", "NOT (severity = error OR severity = warning)", "b,d"},
+		{"double NOT", "NOT NOT severity = error", "a"},
+
+		{"quoted value with spaces", `@message = "request timeout"`, "a"},
+		{"quoted escape", `@message = "request\u0020timeout"`, "a"},
+		{"bare word is full-text", "office", "b"},
+		{"quoted full-text", `"Received HTTP"`, "b"},
+		{"full-text case insensitive", "STARTING", "d"},
+
+		{"numeric greater", "tf_http_res_status_code > 100", "a,b"},
+		{"numeric, not lexicographic", "tf_http_res_status_code < 100", "c"},
+		{"numeric greater or equal", "tf_http_res_status_code >= 500", "a"},
+		{"absent field never ordered", "tf_http_res_status_code <= 1000", "a,b,c"},
+		{"string comparison for non-numbers", "tf_resource_type > t1_vpc_n", "a,b,c"},
+		{"timestamp as string", "timestamp >= 2025-09-09T15:31", "d"},
+
+		{"exists", "tf_resource_type exists", "a,b,c"},
+		{"NOT exists", "NOT tf_http_res_status_code exists", "d"},
+		{"label", "labels.project = billing", "c"},
+		{"label exists", "labels.project exists", "c"},
+		{"missing label", "labels.team exists", ""},
+		{"derived field", "severity = warning", "c"},
+		{"body path", "res_body.error.code = quota_exceeded", "a"},
+		{"body array elements", "res_body.id ~ n-2", "b"},
+		{"body index", "res_body[0].name = office", "b"},
+		{"body null is absent", "res_body[1].name exists", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchIDs(t, tt.query); got != tt.want {
+				t.Errorf("%s: matched %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	tests := []struct {
+		query, want string
+	}{
+		{`@message = "open`, "unterminated string at position 12"},
+		{"tf_rpc => x", `unknown operator "=>" at position 8`},
+		{"nope = 1", `unknown field "nope" at position 1`},
+		{"tf_rpc =", "expected value after = at position 9"},
+		{"tf_rpc = (x)", "expected value after = at position 10"},
+		{"(tf_rpc = x", `expected ")" at position 12`},
+		{"tf_rpc = x)", `unexpected ")" at position 11`},
+		{"AND tf_rpc = x", "unexpected AND at position 1"},
+		{"tf_rpc = x OR", "unexpected end of query at position 14"},
+		{"NOT", "unexpected end of query at position 4"},
+		{"@message ~ (", "expected value after ~ at position 12"},
+		{`@message ~ "a("`, "invalid regexp"},
+	}
+	for _, tt := range tests {
+		_, err := ParseQuery(tt.query)
+		if err == nil {
+			t.Errorf("ParseQuery(%q): no error, want %q", tt.query, tt.want)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParseQuery(%q) = %q, want it to contain %q", tt.query, err, tt.want)
+		}
+	}
+}
+
+func TestFieldValue(t *testing.T) {
+	get, err := FieldValue("res_body.id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	logs := queryLogs()
+	if got := get(&logs[1]); got != "n-1,n-2" {
+		t.Errorf("res_body.id = %q, want n-1,n-2", got)
+	}
+	if got := get(&logs[3]); got != "" {
+		t.Errorf("res_body.id of entry without body = %q, want empty", got)
+	}
+	if _, err := FieldValue("nope"); err == nil {
+		t.Error("FieldValue(nope): no error")
+	}
+	if !slices.Contains(QueryFields(), "severity") {
+		t.Error("QueryFields() lacks severity")
+	}
+}
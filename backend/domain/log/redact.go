@@ -0,0 +1,100 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/url"
+	"strings"
+)
+
+// Redacted заменяет секреты в выгрузках HTTP-транзакций.
+const Redacted = "<redacted>"
+
+// Части имён заголовков, параметров и ключей JSON, значения которых
+// считаются секретами (без учёта регистра, "-" и "_").
+var secretNameParts = []string{
+	"authorization", "cookie", "token", "secret", "password", "passwd",
+	"apikey", "accesskey", "privatekey", "credential", "signature", "session",
+}
+
+func isSecretName(name string) bool {
+	name = strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(name))
+	for _, part := range secretNameParts {
+		if strings.Contains(name, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactHeader скрывает значение заголовка с секретом (Authorization,
+// Cookie, Set-Cookie, X-Auth-Token и т. п.).
+func RedactHeader(name, value string) string {
+	if value != "" && isSecretName(name) {
+		return Redacted
+	}
+	return value
+}
+
+// RedactURI скрывает значения секретных параметров query string; порядок
+// и кодирование остальных параметров сохраняются.
+func RedactURI(uri string) string {
+	path, rawQuery, ok := strings.Cut(uri, "?")
+	if !ok {
+		return uri
+	}
+	return path + "?" + redactParams(rawQuery)
+}
+
+func redactParams(raw string) string {
+	params := strings.Split(raw, "&")
+	for i, param := range params {
+		name, _, _ := strings.Cut(param, "=")
+		if unescaped, err := url.QueryUnescape(name); err == nil && isSecretName(unescaped) {
+			params[i] = name + "=" + Redacted
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+// RedactBody скрывает секреты тела с типом contentType: параметры формы
+// (application/x-www-form-urlencoded, обычный запрос токена) или ключи
+// JSON на любой глубине; значение секретного ключа — объект или массив —
+// скрывается целиком. Прочие тела возвращаются как есть.
+func RedactBody(body, contentType string) string {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil && mediaType == "application/x-www-form-urlencoded" {
+		return redactParams(body)
+	}
+	v := parseBody(body)
+	if v == nil || !redactJSON(v) {
+		return body
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if enc.Encode(v) != nil {
+		return body
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+func redactJSON(v any) bool {
+	changed := false
+	switch node := v.(type) {
+	case map[string]any:
+		for k, child := range node {
+			if child != nil && isSecretName(k) {
+				node[k] = Redacted
+				changed = true
+				continue
+			}
+			changed = redactJSON(child) || changed
+		}
+	case []any:
+		for _, child := range node {
+			changed = redactJSON(child) || changed
+		}
+	}
+	return changed
+}
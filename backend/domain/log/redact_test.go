@@ -0,0 +1,88 @@
+package log
+
+import "testing"
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		name, body, contentType, want string
+	}{
+		{
+			name:        "form",
+			body:        "grant_type=password&client_id=tf&client_secret=s3cr3t&password=p%40ss&username=ci",
+			contentType: "application/x-www-form-urlencoded; charset=utf-8",
+			want:        "grant_type=password&client_id=tf&client_secret=<redacted>&password=<redacted>&username=ci",
+		},
+		{
+			name:        "form without secrets",
+			body:        "a=1&b=2",
+			contentType: "application/x-www-form-urlencoded",
+			want:        "a=1&b=2",
+		},
+		{
+			name:        "json nested",
+			body:        `{"name":"x","auth":{"access_token":"t","user":"u"},"items":[{"client_secret":"s"}]}`,
+			contentType: "application/json",
+			want:        `{"auth":{"access_token":"<redacted>","user":"u"},"items":[{"client_secret":"<redacted>"}],"name":"x"}`,
+		},
+		{
+			name: "json secret object",
+			body: `{"password":{"value":"hunter2"},"credentials":{"user":"u","key":"k"},"name":"x"}`,
+			want: `{"credentials":"<redacted>","name":"x","password":"<redacted>"}`,
+		},
+		{
+			name: "json secret array",
+			body: `{"tokens":["a","b"],"session":null}`,
+			want: `{"session":null,"tokens":"<redacted>"}`,
+		},
+		{
+			name: "json without secrets kept verbatim",
+			body: `{"b": 1, "a": 2}`,
+			want: `{"b": 1, "a": 2}`,
+		},
+		{
+			name: "large numbers keep precision",
+			body: `{"id":12345678901234567890,"token":"t"}`,
+			want: `{"id":12345678901234567890,"token":"<redacted>"}`,
+		},
+		{
+			name:        "plain text",
+			body:        "password=1",
+			contentType: "text/plain",
+			want:        "password=1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RedactBody(tt.body, tt.contentType); got != tt.want {
+				t.Errorf("RedactBody() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactURI(t *testing.T) {
+	tests := []struct{ uri, want string }{
+		{"/a", "/a"},
+		{"/a?x=1", "/a?x=1"},
+		{"/a?token=abc&x=1&flag", "/a?token=<redacted>&x=1&flag"},
+		{"/a?api%5Fkey=k", "/a?api%5Fkey=<redacted>"},
+	}
+	for _, tt := range tests {
+		if got := RedactURI(tt.uri); got != tt.want {
+			t.Errorf("RedactURI(%q) = %q, want %q", tt.uri, got, tt.want)
+		}
+	}
+}
+
+func TestRedactHeader(t *testing.T) {
+	for name, want := range map[string]string{
+		"Authorization": Redacted,
+		"Set-Cookie":    Redacted,
+		"X-Auth-Token":  Redacted,
+		"Content-Type":  "v",
+	} {
+		if got := RedactHeader(name, "v"); got != want {
+			t.Errorf("RedactHeader(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -1,10 +1,73 @@
 package log
 
-import "context"
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrInvalidLine   = errors.New("invalid log line")
+	ErrQuotaExceeded = errors.New("workspace quota exceeded")
+	ErrConflict      = errors.New("conflict")
+	ErrInvalid       = errors.New("invalid argument")
+)
+
+type UploadOptions struct {
+	FileName    string
+	TFWorkspace string
+	RunID       string
+}
 
 type FileUploadResult struct {
-	ID     string `json:"id"`
-	Status string `json:"status"`
+	ID          string             `json:"id"`
+	Status      string             `json:"status"`
+	DuplicateOf string             `json:"duplicate_of,omitempty"`
+	TFWorkspace string             `json:"tf_workspace,omitempty"`
+	Lines       int                `json:"lines"`
+	Corrupted   int                `json:"corrupted"`
+	Duplicates  int                `json:"duplicates"`
+	Novel       []ErrorFingerprint `json:"novel"`
+	Disappeared []ErrorFingerprint `json:"disappeared"`
+}
+
+type Upload struct {
+	ID          string    `json:"id"`
+	FileName    string    `json:"file_name"`
+	SHA256      string    `json:"sha256"`
+	TFWorkspace string    `json:"tf_workspace,omitempty"`
+	RunID       string    `json:"run_id"`
+	UploadedAt  time.Time `json:"uploaded_at"`
+	Lines       int       `json:"lines"`
+	Corrupted   int       `json:"corrupted"`
+	Duplicates  int       `json:"duplicates"`
+	SizeBytes   int64     `json:"size_bytes"` // оценка памяти под записи загрузки
+	Pinned      bool      `json:"pinned"`
+	ViewedAt    time.Time `json:"viewed_at"`
+}
+
+type ErrorFingerprint struct {
+	Fingerprint  string `json:"fingerprint"`
+	Severity     string `json:"severity"`
+	ResourceType string `json:"resource_type,omitempty"`
+	TFRPC        string `json:"tf_rpc,omitempty"`
+	TemplateID   string `json:"template_id"`
+	Template     string `json:"template"`
+	ErrorCode    string `json:"error_code,omitempty"`
+	Summary      string `json:"summary,omitempty"`
+	Count        int    `json:"count"`
+	SampleLogID  string `json:"sample_log_id"`
+}
+
+type NovelReport struct {
+	UploadID         string             `json:"upload_id"`
+	TFWorkspace      string             `json:"tf_workspace,omitempty"`
+	PreviousUploadID string             `json:"previous_upload_id,omitempty"`
+	New              []ErrorFingerprint `json:"new"`
+	Disappeared      []ErrorFingerprint `json:"disappeared"`
 }
 
 type TimelineEntry struct {
@@ -20,25 +83,115 @@ type Metrics struct {
 	Levels   map[string]int `json:"levels"`
 }
 
+type DiagnosticGroup struct {
+	ErrorCode    string      `json:"error_code"`
+	ResourceType string      `json:"resource_type"`
+	Count        int         `json:"count"`
+	HTTPStatuses map[int]int `json:"http_statuses"`
+	Summaries    []string    `json:"summaries"`
+	Attributes   []string    `json:"attributes"`
+	FirstSeen    string      `json:"first_seen"`
+	LastSeen     string      `json:"last_seen"`
+	LogIDs       []string    `json:"log_ids"`
+	// Адреса ресурсов состояния, ID которых упоминают диагностики
+	Addresses []string `json:"addresses"`
+}
+
+type Pattern struct {
+	TemplateID string         `json:"template_id"`
+	Template   string         `json:"template"`
+	Count      int            `json:"count"`
+	FirstSeen  string         `json:"first_seen"`
+	LastSeen   string         `json:"last_seen"`
+	Levels     map[string]int `json:"levels"`
+	Samples    []Log          `json:"samples"`
+}
+
+type LogContext struct {
+	Entry  Log   `json:"entry"`
+	Before []Log `json:"before"`
+	After  []Log `json:"after"`
+}
+
 type ExportFilters struct {
 	TFResourceType string `json:"tf_resource_type,omitempty"`
 	TimestampFrom  string `json:"timestamp_from,omitempty"`
 	TimestampTo    string `json:"timestamp_to,omitempty"`
 	Level          string `json:"level,omitempty"`
 	Search         string `json:"search,omitempty"`
+	Query          string `json:"query,omitempty"` // см. ParseQuery
+	TemplateID     string `json:"template_id,omitempty"`
+	TFReqID        string `json:"tf_req_id,omitempty"`
+	RunID          string `json:"run_id,omitempty"`
+	State          string `json:"state,omitempty"`
+	Assignee       string `json:"assignee,omitempty"`
 	Page           int    `json:"page,omitempty"`
 	Limit          int    `json:"limit,omitempty"`
 }
 
+// Empty сообщает, что фильтры (без учёта пагинации) подходят под любую запись.
+func (f ExportFilters) Empty() bool {
+	f.Page, f.Limit, f.Query = 0, 0, strings.TrimSpace(f.Query)
+	return f == ExportFilters{}
+}
+
 type Repo interface {
-	UploadFile(ctx context.Context, fileData []byte, fileName string) (FileUploadResult, error)
+	UploadFile(ctx context.Context, fileData []byte, opts UploadOptions) (FileUploadResult, error)
 	GetLogs(ctx context.Context, filters ExportFilters) ([]Log, error)
+	// GetAllLogs — все записи под фильтры без пагинации (Page, Limit
+	// игнорируются), снятые под одной блокировкой.
+	GetAllLogs(ctx context.Context, filters ExportFilters) ([]Log, error)
 	GetLogByID(ctx context.Context, id string) (Log, error)
 	MarkLogsRead(ctx context.Context, ids []string) error
 	GetGroupByReqID(ctx context.Context, tfReqID string) ([]Log, error)
 	GetTimelineEntries(ctx context.Context) ([]TimelineEntry, error)
 	GetMetrics(ctx context.Context) (Metrics, error)
 	ExportLogs(ctx context.Context, filters ExportFilters) ([]byte, error)
+	// GetHTTPTransactions — HTTP-транзакции, хотя бы одна запись которых
+	// подходит под фильтры (без пагинации), обе записи транзакции целиком.
+	GetHTTPTransactions(ctx context.Context, filters ExportFilters) ([]HTTPTransaction, error)
 	SendExportToTelegram(ctx context.Context, chatID string, filters ExportFilters) error
-	GetCorruptedLogs(ctx context.Context) ([]string, error)
+	GetCorruptedLogs(ctx context.Context, uploadID string) ([]CorruptedLine, error)
+	ResolveCorruptedLog(ctx context.Context, id string, line string) (Log, error)
+	GetDiagnostics(ctx context.Context) ([]DiagnosticGroup, error)
+	GetPatterns(ctx context.Context) ([]Pattern, error)
+	GetNovelErrors(ctx context.Context, uploadID string) (NovelReport, error)
+	DiffUploads(ctx context.Context, baseID, targetID string) (RunDiff, error)
+	// EvaluateGate проверяет загрузку по порогам CI (см. log.EvaluateGate).
+	EvaluateGate(ctx context.Context, uploadID string, thresholds GateThresholds) (GateReport, error)
+	// GetUIReport — события terraform -json загрузки, сопоставленные с
+	// TF_LOG загрузки traceID (пусто — подобрать автоматически).
+	GetUIReport(ctx context.Context, uploadID, traceID string) (UIReport, error)
+	// AddPlan сохраняет план (terraform show -json) запуска runID,
+	// GetPlan сопоставляет его с TF_LOG загрузок запуска, GetGroupPlan —
+	// изменение плана по ресурсу вызова tfReqID.
+	AddPlan(ctx context.Context, data []byte, runID, fileName string) (Plan, error)
+	GetPlan(ctx context.Context, runID string) (PlanReport, error)
+	GetGroupPlan(ctx context.Context, tfReqID string) (GroupPlan, error)
+	// SetState заменяет состояние terraform пространства (индекс ID ресурсов
+	// по адресам) и размечает им записи, см. State.Annotate.
+	SetState(ctx context.Context, data []byte, fileName string) (State, error)
+	GetState(ctx context.Context) (State, error)
+	// GetEndpointAnalytics — статистика вызовов API облака по шаблонам URI
+	// для загрузок запуска runID (пусто — всех загрузок).
+	GetEndpointAnalytics(ctx context.Context, runID string) ([]EndpointStats, error)
+	GetLogContext(ctx context.Context, id string, before, after int, window time.Duration) (LogContext, error)
+	UpdateTriage(ctx context.Context, ids []string, filters *ExportFilters, update TriageUpdate) (int, error)
+	AddNote(ctx context.Context, target NoteTarget, author, text string) (Note, error)
+	GetNotes(ctx context.Context, target NoteTarget) ([]Note, error)
+	CreateWorkspace(ctx context.Context, ws Workspace) (Workspace, error)
+	ListWorkspaces(ctx context.Context) ([]Workspace, error)
+	GetWorkspace(ctx context.Context, id string) (Workspace, error)
+	DeleteWorkspace(ctx context.Context, id string) error
+	ListUploads(ctx context.Context) ([]Upload, error)
+	PinUpload(ctx context.Context, id string, pinned bool) error
+	// ApplyRetention вытесняет загрузки всех пространств по политике.
+	ApplyRetention(ctx context.Context, policy RetentionPolicy) (RetentionReport, error)
+	GetUsage(ctx context.Context) (Usage, error)
+	// Snapshot пишет состояние всех пространств в сжатом версионированном
+	// формате, Restore полностью заменяет им текущее состояние.
+	Snapshot(ctx context.Context, w io.Writer) error
+	Restore(ctx context.Context, r io.Reader) error
+	// Subscribe сообщает о новых загрузках пространства из ctx до его отмены.
+	Subscribe(ctx context.Context) (<-chan UploadEvent, error)
 }
@@ -0,0 +1,49 @@
+package log
+
+import "time"
+
+type EvictOrder string
+
+const (
+	EvictOldest EvictOrder = "oldest" // по времени загрузки
+	EvictLRU    EvictOrder = "lru"    // по времени последнего просмотра
+)
+
+// RetentionPolicy — ограничения хранилища; нулевые значения отключают
+// соответствующую проверку. Закреплённые загрузки не вытесняются.
+type RetentionPolicy struct {
+	MaxAge         time.Duration
+	MaxEntries     int
+	MaxMemoryBytes int64
+	Evict          EvictOrder
+}
+
+type EvictedUpload struct {
+	Workspace string `json:"workspace"`
+	UploadID  string `json:"upload_id"`
+	FileName  string `json:"file_name"`
+	Entries   int    `json:"entries"`
+	Reason    string `json:"reason"` // max_age, max_entries, max_memory
+}
+
+type RetentionReport struct {
+	At          time.Time       `json:"at"`
+	Evicted     []EvictedUpload `json:"evicted"`
+	Entries     int             `json:"entries"`
+	MemoryBytes int64           `json:"memory_bytes"`
+}
+
+type WorkspaceUsage struct {
+	Workspace   string `json:"workspace,omitempty"`
+	Uploads     int    `json:"uploads"`
+	Pinned      int    `json:"pinned"`
+	Entries     int    `json:"entries"`
+	Corrupted   int    `json:"corrupted"`
+	MemoryBytes int64  `json:"memory_bytes"`
+}
+
+// Usage — занятость хранилища; MemoryBytes — оценка, а не точный замер.
+type Usage struct {
+	WorkspaceUsage
+	Workspaces []WorkspaceUsage `json:"workspaces"`
+}
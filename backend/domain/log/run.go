@@ -0,0 +1,275 @@
+package log
+
+import (
+	"maps"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+type ResourceRunStats struct {
+	Outcome     string         `json:"outcome"`
+	RPCCount    int            `json:"rpc_count"`
+	RPCs        map[string]int `json:"rpcs"`
+	DurationMs  int            `json:"duration_ms"`
+	Endpoints   map[string]int `json:"endpoints"`
+	StatusCodes map[int]int    `json:"status_codes"`
+	Errors      []string       `json:"errors"`
+
+	reqIDs map[string]bool
+}
+
+type ResourceDiff struct {
+	Address          string            `json:"address"`
+	Change           string            `json:"change"` // added, removed, changed, unchanged
+	Base             *ResourceRunStats `json:"base,omitempty"`
+	Target           *ResourceRunStats `json:"target,omitempty"`
+	NewErrors        []string          `json:"new_errors"`
+	RemovedErrors    []string          `json:"removed_errors"`
+	NewEndpoints     []string          `json:"new_endpoints"`
+	RemovedEndpoints []string          `json:"removed_endpoints"`
+}
+
+type ConfigChange struct {
+	Key    string `json:"key"`
+	Base   string `json:"base"`
+	Target string `json:"target"`
+}
+
+type RunDiff struct {
+	Base      string         `json:"base"`
+	Target    string         `json:"target"`
+	Config    []ConfigChange `json:"config"`
+	Resources []ResourceDiff `json:"resources"`
+}
+
+var (
+	addressRe        = regexp.MustCompile(`(?:data\.)?[a-z][a-z0-9]*_[a-z0-9_]+\.[A-Za-z_][A-Za-z0-9_-]*(?:\[[^\]\s]+\])?`)
+	providerModuleRe = regexp.MustCompile(`^provider\.terraform-provider-([a-z0-9_-]+?)_v?(\d+\.\d+\.\d+[^_]*)`)
+	configMessageRes = map[string]*regexp.Regexp{
+		"terraform_version": regexp.MustCompile(`^Terraform version: (\S+)`),
+		"go_runtime":        regexp.MustCompile(`^Go runtime version: (\S+)`),
+		"command":           regexp.MustCompile(`^CLI command args: (.+)$`),
+	}
+)
+
+// RunConfig собирает параметры запуска: версии terraform, провайдеров,
+// протокола и аргументы командной строки.
+func RunConfig(logs []Log) map[string]string {
+	conf := make(map[string]string)
+	for _, l := range logs {
+		if m := providerModuleRe.FindStringSubmatch(l.At_module); m != nil {
+			conf["provider."+m[1]] = m[2]
+		}
+		if l.Tf_proto_version != "" {
+			conf["tf_proto_version"] = l.Tf_proto_version
+		}
+		if l.Tf_provider_addr != "" {
+			conf["provider_addr"] = l.Tf_provider_addr
+		}
+		if l.At_module != "" {
+			continue
+		}
+		for key, re := range configMessageRes {
+			if m := re.FindStringSubmatch(l.At_message); m != nil {
+				conf[key] = m[1]
+			}
+		}
+	}
+	return conf
+}
+
+// ResourceAddresses находит адреса ресурсов (t1_vpc_vip.foo,
+// data.t1_vpc_network.default) в сообщениях ядра terraform и группирует их
+// по типу ресурса (для data-источников тип с префиксом data.).
+func ResourceAddresses(logs []Log) map[string][]string {
+	byType := make(map[string][]string)
+	for _, l := range logs {
+		for _, addr := range messageAddresses(l) {
+			typ := addressType(addr)
+			if !slices.Contains(byType[typ], addr) {
+				byType[typ] = append(byType[typ], addr)
+			}
+		}
+	}
+	for _, addrs := range byType {
+		sort.Strings(addrs)
+	}
+	return byType
+}
+
+func messageAddresses(l Log) []string {
+	if l.At_module != "" || l.Tf_req_id != "" {
+		return nil
+	}
+	var addrs []string
+	for _, addr := range addressRe.FindAllString(l.At_message, -1) {
+		if !slices.Contains(addrs, addr) {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+func addressType(addr string) string {
+	data := strings.HasPrefix(addr, "data.")
+	addr = strings.TrimPrefix(addr, "data.")
+	typ, _, _ := strings.Cut(addr, ".")
+	if data {
+		return "data." + typ
+	}
+	return typ
+}
+
+// ResourceAddress определяет адрес ресурса записи. Записи провайдера знают
+// только тип; если в запуске ровно один ресурс этого типа, берётся его
+// адрес, иначе — сам тип. Типы, которых нет в конфигурации запуска
+// (провайдер валидирует схемы всех своих типов), пропускаются.
+func ResourceAddress(l Log, byType map[string][]string) string {
+	if typ := ResourceType(l); typ != "" {
+		switch addrs := byType[typ]; {
+		case len(addrs) == 1:
+			return addrs[0]
+		case len(addrs) == 0 && len(byType) > 0:
+			return ""
+		}
+		return typ
+	}
+	if addrs := messageAddresses(l); len(addrs) == 1 {
+		return addrs[0]
+	}
+	return ""
+}
+
+// NormalizedError — сообщение об ошибке без переменных частей, пригодное
+// для сопоставления между запусками.
+func NormalizedError(l Log) string {
+	msg := strings.Join(TokenizeMessage(l.At_message), " ")
+	if l.Diagnostic_summary != "" {
+		msg += ": " + strings.Join(TokenizeMessage(l.Diagnostic_summary), " ")
+	}
+	if l.Diag != nil && l.Diag.ErrorCode != "" {
+		msg += " [" + l.Diag.ErrorCode + "]"
+	}
+	return msg
+}
+
+func RunResources(logs []Log) map[string]*ResourceRunStats {
+	byType := ResourceAddresses(logs)
+	resources := make(map[string]*ResourceRunStats)
+	for _, l := range logs {
+		// GetProviderSchema перечисляет все типы провайдера — это не работа с ресурсом
+		if l.Tf_rpc == "GetProviderSchema" {
+			continue
+		}
+		addr := ResourceAddress(l, byType)
+		if addr == "" {
+			continue
+		}
+		s, ok := resources[addr]
+		if !ok {
+			s = &ResourceRunStats{
+				Outcome:     "ok",
+				RPCs:        make(map[string]int),
+				Endpoints:   make(map[string]int),
+				StatusCodes: make(map[int]int),
+				Errors:      []string{},
+				reqIDs:      make(map[string]bool),
+			}
+			resources[addr] = s
+		}
+		if l.Tf_req_id != "" && l.Tf_rpc != "" && !s.reqIDs[l.Tf_req_id] {
+			s.reqIDs[l.Tf_req_id] = true
+			s.RPCCount++
+			s.RPCs[l.Tf_rpc]++
+		}
+		s.DurationMs += l.Tf_req_duration_ms
+		if l.Tf_http_op_type == "request" && l.Tf_http_req_uri != "" {
+			s.Endpoints[l.Tf_http_req_method+" "+NormalizeURI(l.Tf_http_req_uri)]++
+		}
+		if l.Tf_http_res_status_code != 0 {
+			s.StatusCodes[l.Tf_http_res_status_code]++
+		}
+		switch Severity(l) {
+		case "error":
+			s.Outcome = "error"
+			if msg := NormalizedError(l); !slices.Contains(s.Errors, msg) {
+				s.Errors = append(s.Errors, msg)
+			}
+		case "warning":
+			if s.Outcome == "ok" {
+				s.Outcome = "warning"
+			}
+		}
+	}
+	for _, s := range resources {
+		sort.Strings(s.Errors)
+	}
+	return resources
+}
+
+func DiffRuns(base, target []Log) RunDiff {
+	diff := RunDiff{Config: []ConfigChange{}, Resources: []ResourceDiff{}}
+
+	baseConf, targetConf := RunConfig(base), RunConfig(target)
+	keys := slices.Sorted(maps.Keys(baseConf))
+	for k := range targetConf {
+		if _, ok := baseConf[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if baseConf[k] != targetConf[k] {
+			diff.Config = append(diff.Config, ConfigChange{Key: k, Base: baseConf[k], Target: targetConf[k]})
+		}
+	}
+
+	baseRes, targetRes := RunResources(base), RunResources(target)
+	addrs := slices.Collect(maps.Keys(baseRes))
+	for addr := range targetRes {
+		if _, ok := baseRes[addr]; !ok {
+			addrs = append(addrs, addr)
+		}
+	}
+	sort.Strings(addrs)
+	for _, addr := range addrs {
+		b, t := baseRes[addr], targetRes[addr]
+		d := ResourceDiff{Address: addr, Base: b, Target: t}
+		var bErrs, tErrs, bEps, tEps []string
+		if b != nil {
+			bErrs, bEps = b.Errors, slices.Sorted(maps.Keys(b.Endpoints))
+		}
+		if t != nil {
+			tErrs, tEps = t.Errors, slices.Sorted(maps.Keys(t.Endpoints))
+		}
+		d.NewErrors, d.RemovedErrors = setDiff(tErrs, bErrs), setDiff(bErrs, tErrs)
+		d.NewEndpoints, d.RemovedEndpoints = setDiff(tEps, bEps), setDiff(bEps, tEps)
+		switch {
+		case b == nil:
+			d.Change = "added"
+		case t == nil:
+			d.Change = "removed"
+		case b.Outcome != t.Outcome || b.RPCCount != t.RPCCount ||
+			!maps.Equal(b.StatusCodes, t.StatusCodes) || len(d.NewErrors) > 0 ||
+			len(d.RemovedErrors) > 0 || len(d.NewEndpoints) > 0 || len(d.RemovedEndpoints) > 0:
+			d.Change = "changed"
+		default:
+			d.Change = "unchanged"
+		}
+		diff.Resources = append(diff.Resources, d)
+	}
+	return diff
+}
+
+// setDiff возвращает элементы a, которых нет в b.
+func setDiff(a, b []string) []string {
+	res := []string{}
+	for _, s := range a {
+		if !slices.Contains(b, s) {
+			res = append(res, s)
+		}
+	}
+	return res
+}
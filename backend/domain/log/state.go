@@ -0,0 +1,302 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Короче этого ID ресурсов не индексируются: совпадения с числами и
+// словами в телах запросов были бы случайными.
+const (
+	minStateIDLen      = 6
+	minStateSegmentLen = 8
+)
+
+// State — индекс состояния terraform (terraform.tfstate или
+// `terraform show -json`): атрибуты ресурсов по адресам и адреса по ID.
+type State struct {
+	FileName   string          `json:"file_name"`
+	UploadedAt time.Time       `json:"uploaded_at"`
+	Terraform  string          `json:"terraform_version,omitempty"`
+	Serial     int64           `json:"serial,omitempty"`
+	Lineage    string          `json:"lineage,omitempty"`
+	Resources  []StateResource `json:"resources"`
+	// IDs — ID ресурса (или однозначная часть составного ID) -> адреса
+	IDs map[string][]string `json:"ids"`
+}
+
+type StateResource struct {
+	Address    string         `json:"address"`
+	Mode       string         `json:"mode"` // managed, data
+	Type       string         `json:"type"`
+	Name       string         `json:"name"`
+	Provider   string         `json:"provider,omitempty"`
+	ID         string         `json:"id,omitempty"`
+	Attributes map[string]any `json:"attributes"`
+}
+
+// tfstateJSON — файл состояния версии 4.
+type tfstateJSON struct {
+	Version          int    `json:"version"`
+	TerraformVersion string `json:"terraform_version"`
+	Serial           int64  `json:"serial"`
+	Lineage          string `json:"lineage"`
+	Resources        []struct {
+		Module    string `json:"module"`
+		Mode      string `json:"mode"`
+		Type      string `json:"type"`
+		Name      string `json:"name"`
+		Provider  string `json:"provider"`
+		Instances []struct {
+			IndexKey            any               `json:"index_key"`
+			Attributes          map[string]any    `json:"attributes"`
+			SensitiveAttributes []json.RawMessage `json:"sensitive_attributes"`
+		} `json:"instances"`
+	} `json:"resources"`
+}
+
+// showStateJSON — `terraform show -json` состояния или prior_state плана.
+type showStateJSON struct {
+	FormatVersion    string          `json:"format_version"`
+	TerraformVersion string          `json:"terraform_version"`
+	Values           *showValuesJSON `json:"values"`
+	PriorState       *showStateJSON  `json:"prior_state"`
+	PlannedValues    json.RawMessage `json:"planned_values"`
+}
+
+type showValuesJSON struct {
+	RootModule showModuleJSON `json:"root_module"`
+}
+
+type showModuleJSON struct {
+	Resources []struct {
+		Address         string         `json:"address"`
+		Mode            string         `json:"mode"`
+		Type            string         `json:"type"`
+		Name            string         `json:"name"`
+		ProviderName    string         `json:"provider_name"`
+		Values          map[string]any `json:"values"`
+		SensitiveValues any            `json:"sensitive_values"`
+	} `json:"resources"`
+	ChildModules []showModuleJSON `json:"child_modules"`
+}
+
+// ParseState разбирает terraform.tfstate (version 4) или `terraform show
+// -json` состояния; у плана берётся prior_state. Sensitive-значения скрываются.
+func ParseState(data []byte) (State, error) {
+	var show showStateJSON
+	if err := json.Unmarshal(data, &show); err != nil {
+		return State{}, fmt.Errorf("state: %v: %w", err, ErrInvalid)
+	}
+	switch {
+	case show.PriorState != nil:
+		show = *show.PriorState
+	case show.PlannedValues != nil:
+		return State{}, fmt.Errorf("plan without prior_state: %w", ErrInvalid)
+	}
+	state := State{Resources: []StateResource{}}
+	switch {
+	case show.FormatVersion != "":
+		state.Terraform = show.TerraformVersion
+		if show.Values != nil {
+			state.addShowModule(show.Values.RootModule)
+		}
+	default:
+		var raw tfstateJSON
+		if err := json.Unmarshal(data, &raw); err != nil || raw.Version == 0 {
+			return State{}, fmt.Errorf("not a terraform state: %w", ErrInvalid)
+		}
+		if raw.Version != 4 {
+			return State{}, fmt.Errorf("state version %d: %w", raw.Version, ErrInvalid)
+		}
+		state.Terraform, state.Serial, state.Lineage = raw.TerraformVersion, raw.Serial, raw.Lineage
+		for _, res := range raw.Resources {
+			base := res.Type + "." + res.Name
+			if res.Mode == "data" {
+				base = "data." + base
+			}
+			if res.Module != "" {
+				base = res.Module + "." + base
+			}
+			for _, inst := range res.Instances {
+				state.add(StateResource{
+					Address:    base + indexSuffix(inst.IndexKey),
+					Mode:       res.Mode,
+					Type:       res.Type,
+					Name:       res.Name,
+					Provider:   res.Provider,
+					Attributes: maskSensitivePaths(inst.Attributes, inst.SensitiveAttributes),
+				})
+			}
+		}
+	}
+	sort.Slice(state.Resources, func(i, j int) bool {
+		return state.Resources[i].Address < state.Resources[j].Address
+	})
+	state.buildIndex()
+	return state, nil
+}
+
+func (s *State) addShowModule(m showModuleJSON) {
+	for _, res := range m.Resources {
+		attrs, _ := maskSensitive(res.Values, res.SensitiveValues).(map[string]any)
+		s.add(StateResource{
+			Address:    res.Address,
+			Mode:       res.Mode,
+			Type:       res.Type,
+			Name:       res.Name,
+			Provider:   res.ProviderName,
+			Attributes: attrs,
+		})
+	}
+	for _, child := range m.ChildModules {
+		s.addShowModule(child)
+	}
+}
+
+func (s *State) add(res StateResource) {
+	if res.Attributes == nil {
+		res.Attributes = map[string]any{}
+	}
+	res.ID, _ = res.Attributes["id"].(string)
+	s.Resources = append(s.Resources, res)
+}
+
+func indexSuffix(key any) string {
+	switch k := key.(type) {
+	case float64:
+		return "[" + strconv.Itoa(int(k)) + "]"
+	case string:
+		return "[" + strconv.Quote(k) + "]"
+	}
+	return ""
+}
+
+// maskSensitivePaths скрывает атрибуты из sensitive_attributes файла
+// состояния: пути [{"type":"get_attr","value":"password"}] или ["password"].
+func maskSensitivePaths(attrs map[string]any, paths []json.RawMessage) map[string]any {
+	for _, raw := range paths {
+		var steps []struct {
+			Type  string `json:"type"`
+			Value string `json:"value"`
+		}
+		name := ""
+		if json.Unmarshal(raw, &steps) == nil && len(steps) > 0 && steps[0].Type == "get_attr" {
+			name = steps[0].Value
+		} else {
+			var plain []any
+			if json.Unmarshal(raw, &plain) == nil && len(plain) > 0 {
+				name, _ = plain[0].(string)
+			}
+		}
+		if v, ok := attrs[name]; ok && v != nil {
+			attrs[name] = sensitiveValue
+		}
+	}
+	return attrs
+}
+
+// buildIndex связывает ID с адресами. У составных ID (project/uuid)
+// индексируется последняя часть — собственный ID ресурса; родительские
+// (проект) встречаются в URI почти всех запросов. Часть берётся, только если
+// однозначна и не совпадает с другим ID. Если ID есть у управляемого ресурса,
+// data-источники с ним не учитываются.
+func (s *State) buildIndex() {
+	full := make(map[string][]StateResource)
+	parts := make(map[string][]StateResource)
+	for _, res := range s.Resources {
+		if len(res.ID) < minStateIDLen {
+			continue
+		}
+		full[res.ID] = append(full[res.ID], res)
+		if i := strings.LastIndexAny(res.ID, "/:"); i >= 0 {
+			if part := res.ID[i+1:]; len(part) >= minStateSegmentLen {
+				parts[part] = append(parts[part], res)
+			}
+		}
+	}
+	for part, owners := range parts {
+		if _, ok := full[part]; !ok && len(owners) == 1 {
+			full[part] = owners
+		}
+	}
+	s.IDs = make(map[string][]string, len(full))
+	for id, owners := range full {
+		managed := slices.ContainsFunc(owners, func(r StateResource) bool { return r.Mode != "data" })
+		var addrs []string
+		for _, r := range owners {
+			if managed && r.Mode == "data" || slices.Contains(addrs, r.Address) {
+				continue
+			}
+			addrs = append(addrs, r.Address)
+		}
+		sort.Strings(addrs)
+		s.IDs[id] = addrs
+	}
+}
+
+// Mentions возвращает адреса ресурсов, ID которых встречаются в тексте.
+func (s *State) Mentions(text string) []string {
+	var addrs []string
+	for _, token := range strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' && r != '.'
+	}) {
+		for _, addr := range s.IDs[strings.Trim(token, ".")] {
+			if !slices.Contains(addrs, addr) {
+				addrs = append(addrs, addr)
+			}
+		}
+	}
+	sort.Strings(addrs)
+	return addrs
+}
+
+// Annotate заполняет StateAddresses записям HTTP-транзакций и диагностик,
+// упоминающим ID ресурсов. Обе записи транзакции получают адреса из запроса
+// и ответа. Без состояния (s == nil) аннотации снимаются.
+func (s *State) Annotate(logs []Log) {
+	byTrans := make(map[string][]string)
+	for i := range logs {
+		l := &logs[i]
+		l.StateAddresses = nil
+		if s == nil {
+			continue
+		}
+		var text string
+		switch {
+		case l.Tf_http_trans_id != "":
+			text = strings.Join([]string{l.Tf_http_req_uri, l.Tf_http_req_body, l.Tf_http_res_body}, " ")
+		case isStateDiagnostic(*l):
+			text = strings.Join([]string{l.At_message, l.Diagnostic_summary, l.Diagnostic_detail}, " ")
+			if l.Diag != nil {
+				text += " " + string(l.Diag.Body)
+			}
+		default:
+			continue
+		}
+		l.StateAddresses = s.Mentions(text)
+		if id := l.Tf_http_trans_id; id != "" {
+			for _, addr := range l.StateAddresses {
+				if !slices.Contains(byTrans[id], addr) {
+					byTrans[id] = append(byTrans[id], addr)
+				}
+			}
+		}
+	}
+	for i := range logs {
+		if addrs, ok := byTrans[logs[i].Tf_http_trans_id]; ok {
+			sort.Strings(addrs)
+			logs[i].StateAddresses = addrs
+		}
+	}
+}
+
+func isStateDiagnostic(l Log) bool {
+	return l.Diagnostic_summary != "" || l.Diag != nil || Severity(l) == "error"
+}
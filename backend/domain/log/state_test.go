@@ -0,0 +1,111 @@
+package log
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseStateSensitiveAttributes(t *testing.T) {
+	const tfstate = `{
+  "version": 4, "terraform_version": "1.13.1", "serial": 7, "lineage": "l-1",
+  "resources": [{
+    "mode": "managed", "type": "t1_db_user", "name": "app",
+    "provider": "provider[\"registry.terraform.io/t1/t1cloud\"]",
+    "instances": [{
+      "index_key": 0,
+      "attributes": {"id": "user-12345678", "name": "app", "password": "p", "token": "t", "api_key": null},
+      "sensitive_attributes": [
+        [{"type": "get_attr", "value": "password"}],
+        ["token"],
+        [{"type": "get_attr", "value": "api_key"}],
+        [{"type": "get_attr", "value": "missing"}]
+      ]
+    }]
+  }]
+}`
+	state, err := ParseState([]byte(tfstate))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Serial != 7 || len(state.Resources) != 1 {
+		t.Fatalf("state = %+v", state)
+	}
+	res := state.Resources[0]
+	if res.Address != "t1_db_user.app[0]" || res.ID != "user-12345678" {
+		t.Errorf("resource %s id %s", res.Address, res.ID)
+	}
+	if got := encodeJSON(t, res.Attributes); got != `{"api_key":null,"id":"user-12345678","name":"app","password":"(sensitive)","token":"(sensitive)"}` {
+		t.Errorf("attributes = %s", got)
+	}
+}
+
+func TestParseStateShowJSON(t *testing.T) {
+	const show = `{
+  "format_version": "1.0", "terraform_version": "1.13.1",
+  "values": {"root_module": {
+    "resources": [{
+      "address": "t1_vpc_vip.office", "mode": "managed", "type": "t1_vpc_vip", "name": "office",
+      "values": {"id": "vip-12345678", "auth": {"user": "u", "secret": "s"}},
+      "sensitive_values": {"auth": {"secret": true}}
+    }],
+    "child_modules": [{"resources": [{
+      "address": "module.net.t1_vpc_network.main", "mode": "managed", "type": "t1_vpc_network", "name": "main",
+      "values": {"id": "net-12345678"}
+    }]}]
+  }}
+}`
+	state, err := ParseState([]byte(show))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Resources) != 2 || state.Resources[0].Address != "module.net.t1_vpc_network.main" {
+		t.Fatalf("resources = %+v", state.Resources)
+	}
+	if got := encodeJSON(t, state.Resources[1].Attributes); got != `{"auth":{"secret":"(sensitive)","user":"u"},"id":"vip-12345678"}` {
+		t.Errorf("attributes = %s", got)
+	}
+	if _, err := ParseState([]byte(testPlan)); !errors.Is(err, ErrInvalid) {
+		t.Errorf("plan without prior_state: %v, want ErrInvalid", err)
+	}
+}
+
+func TestStateMentions(t *testing.T) {
+	s := State{Resources: []StateResource{
+		{Address: "t1_vpc_vip.a", Mode: "managed", ID: "proj-rb4czryqmoxtox1/3f2a1b4c-0000-4000-8000-00000000000a"},
+		{Address: "t1_vpc_vip.b", Mode: "managed", ID: "proj-rb4czryqmoxtox1/3f2a1b4c-0000-4000-8000-00000000000b"},
+		// Одинаковая последняя часть у двух ресурсов — неоднозначна
+		{Address: "t1_dns_record.x", Mode: "managed", ID: "zone-1/record-shared1"},
+		{Address: "t1_dns_record.y", Mode: "managed", ID: "zone-2/record-shared1"},
+		// Последняя часть совпадает с ID другого ресурса
+		{Address: "t1_vpc_network.main", Mode: "managed", ID: "net-abcdefgh"},
+		{Address: "t1_vpc_subnet.s", Mode: "managed", ID: "proj-1/net-abcdefgh"},
+		{Address: "data.t1_vpc_network.main", Mode: "data", ID: "net-abcdefgh"},
+		{Address: "data.t1_image.ubuntu", Mode: "data", ID: "img-ubuntu-22"},
+		{Address: "t1_tag.short", Mode: "managed", ID: "t-1"},
+		{Address: "t1_tag.seg", Mode: "managed", ID: "p/short"},
+	}}
+	s.buildIndex()
+
+	tests := []struct {
+		text, want string
+	}{
+		{"GET /v1/projects/proj-rb4czryqmoxtox1/vips/3f2a1b4c-0000-4000-8000-00000000000a", "t1_vpc_vip.a"},
+		{`{"ids":["3f2a1b4c-0000-4000-8000-00000000000a","3f2a1b4c-0000-4000-8000-00000000000b"]}`, "t1_vpc_vip.a,t1_vpc_vip.b"},
+		{"project proj-rb4czryqmoxtox1 only", ""},
+		{"record record-shared1", ""},
+		{"zone-1/record-shared1", ""},
+		{"network net-abcdefgh.", "t1_vpc_network.main"},
+		{"image img-ubuntu-22", "data.t1_image.ubuntu"},
+		{"tag t-1 short", ""},
+		{"segment short", ""},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(s.Mentions(tt.text), ","); got != tt.want {
+			t.Errorf("Mentions(%q) = %s, want %s", tt.text, got, tt.want)
+		}
+	}
+	if _, ok := s.IDs["p/short"]; !ok {
+		t.Error("full composite ID is not indexed")
+	}
+}
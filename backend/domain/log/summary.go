@@ -0,0 +1,204 @@
+package log
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// RPCCall — один gRPC-вызов terraform к провайдеру (записи с общим tf_req_id).
+type RPCCall struct {
+	TFReqID    string    `json:"tf_req_id"`
+	RPC        string    `json:"rpc"`
+	Resource   string    `json:"resource,omitempty"`
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	DurationMs int       `json:"duration_ms"`
+	Severity   string    `json:"severity,omitempty"`
+	HTTPCalls  int       `json:"http_calls"`
+	Entries    int       `json:"entries"`
+}
+
+// RPCCalls группирует записи по tf_req_id в порядке начала вызова.
+// Длительность — tf_req_duration_ms провайдера или разница крайних записей.
+func RPCCalls(logs []Log) []RPCCall {
+	byType := ResourceAddresses(logs)
+	calls := make(map[string]*RPCCall)
+	for _, l := range logs {
+		if l.Tf_req_id == "" {
+			continue
+		}
+		c, ok := calls[l.Tf_req_id]
+		if !ok {
+			c = &RPCCall{TFReqID: l.Tf_req_id}
+			calls[l.Tf_req_id] = c
+		}
+		c.Entries++
+		if c.RPC == "" {
+			c.RPC = l.Tf_rpc
+		}
+		if c.Resource == "" {
+			c.Resource = ResourceAddress(l, byType)
+		}
+		if at, ok := Time(l); ok {
+			if c.Start.IsZero() || at.Before(c.Start) {
+				c.Start = at
+			}
+			if at.After(c.End) {
+				c.End = at
+			}
+		}
+		c.DurationMs = max(c.DurationMs, l.Tf_req_duration_ms)
+		if l.Tf_http_op_type == "request" {
+			c.HTTPCalls++
+		}
+		if s := Severity(l); s == "error" || (s == "warning" && c.Severity == "") {
+			c.Severity = s
+		}
+	}
+	result := make([]RPCCall, 0, len(calls))
+	for _, c := range calls {
+		if !c.Start.IsZero() {
+			c.DurationMs = max(c.DurationMs, int(c.End.Sub(c.Start).Milliseconds()))
+		}
+		result = append(result, *c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].Start.Equal(result[j].Start) {
+			return result[i].Start.Before(result[j].Start)
+		}
+		return result[i].TFReqID < result[j].TFReqID
+	})
+	return result
+}
+
+type RunSummary struct {
+	Entries      int               `json:"entries"`
+	Start        time.Time         `json:"start"`
+	End          time.Time         `json:"end"`
+	DurationMs   int               `json:"duration_ms"`
+	Config       map[string]string `json:"config"`
+	Levels       map[string]int    `json:"levels"`
+	Errors       int               `json:"errors"`
+	Warnings     int               `json:"warnings"`
+	Resources    map[string]string `json:"resources"` // адрес -> ok, warning, error
+	RPCs         int               `json:"rpcs"`
+	HTTPRequests int               `json:"http_requests"`
+	HTTPStatuses map[int]int       `json:"http_statuses"`
+	SlowestRPCs  []RPCCall         `json:"slowest_rpcs"`
+}
+
+// Summarize собирает сводку запуска: параметры, уровни, ошибки, исходы
+// ресурсов и top самых долгих RPC.
+func Summarize(logs []Log, top int) RunSummary {
+	s := RunSummary{
+		Entries:      len(logs),
+		Config:       RunConfig(logs),
+		Levels:       make(map[string]int),
+		Resources:    make(map[string]string),
+		HTTPStatuses: make(map[int]int),
+	}
+	for _, l := range logs {
+		if at, ok := Time(l); ok {
+			if s.Start.IsZero() || at.Before(s.Start) {
+				s.Start = at
+			}
+			if at.After(s.End) {
+				s.End = at
+			}
+		}
+		level := strings.ToLower(l.At_level)
+		if level == "" {
+			level = "unknown"
+		}
+		s.Levels[level]++
+		switch Severity(l) {
+		case "error":
+			s.Errors++
+		case "warning":
+			s.Warnings++
+		}
+		if l.Tf_http_op_type == "request" {
+			s.HTTPRequests++
+		}
+		if l.Tf_http_res_status_code != 0 {
+			s.HTTPStatuses[l.Tf_http_res_status_code]++
+		}
+	}
+	if !s.Start.IsZero() {
+		s.DurationMs = int(s.End.Sub(s.Start).Milliseconds())
+	}
+	for addr, r := range RunResources(logs) {
+		s.Resources[addr] = r.Outcome
+	}
+	calls := RPCCalls(logs)
+	s.RPCs = len(calls)
+	sort.SliceStable(calls, func(i, j int) bool { return calls[i].DurationMs > calls[j].DurationMs })
+	s.SlowestRPCs = calls[:min(top, len(calls))]
+	return s
+}
+
+// ErrorReport — ошибка или предупреждение вместе с HTTP-обменом того же
+// RPC; одинаковые по отпечатку записи объединяются.
+type ErrorReport struct {
+	Fingerprint string            `json:"fingerprint"`
+	Severity    string            `json:"severity"`
+	Count       int               `json:"count"`
+	Timestamp   string            `json:"timestamp"`
+	Resource    string            `json:"resource,omitempty"`
+	RPC         string            `json:"rpc,omitempty"`
+	TFReqID     string            `json:"tf_req_id,omitempty"`
+	Message     string            `json:"message"`
+	Summary     string            `json:"summary,omitempty"`
+	Detail      string            `json:"detail,omitempty"`
+	Diag        *Diagnostic       `json:"diag,omitempty"`
+	HTTP        []HTTPTransaction `json:"http"`
+	Addresses   []string          `json:"addresses,omitempty"` // из состояния, см. State.Annotate
+}
+
+// ErrorReports требует заполненного TemplateID (см. Fingerprint).
+func ErrorReports(logs []Log, warnings bool) []ErrorReport {
+	byType := ResourceAddresses(logs)
+	txByReq := make(map[string][]HTTPTransaction)
+	for _, t := range HTTPTransactions(logs) {
+		txByReq[t.TFReqID] = append(txByReq[t.TFReqID], t)
+	}
+	reports := make(map[string]*ErrorReport)
+	var order []string
+	for _, l := range logs {
+		sev := Severity(l)
+		if sev != "error" && !(warnings && sev == "warning") {
+			continue
+		}
+		fp := Fingerprint(l)
+		if r, ok := reports[fp]; ok {
+			r.Count++
+			continue
+		}
+		r := &ErrorReport{
+			Fingerprint: fp,
+			Severity:    sev,
+			Count:       1,
+			Timestamp:   l.At_timestamp,
+			Resource:    ResourceAddress(l, byType),
+			RPC:         l.Tf_rpc,
+			TFReqID:     l.Tf_req_id,
+			Message:     l.At_message,
+			Summary:     strings.TrimSpace(l.Diagnostic_summary),
+			Detail:      strings.TrimSpace(l.Diagnostic_detail),
+			Diag:        l.Diag,
+			HTTP:        []HTTPTransaction{},
+			Addresses:   l.StateAddresses,
+		}
+		if l.Tf_req_id != "" {
+			r.HTTP = append(r.HTTP, txByReq[l.Tf_req_id]...)
+		}
+		reports[fp] = r
+		order = append(order, fp)
+	}
+	result := make([]ErrorReport, 0, len(order))
+	for _, fp := range order {
+		result = append(result, *reports[fp])
+	}
+	return result
+}
@@ -0,0 +1,227 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Майнинг шаблонов сообщений по алгоритму Drain: дерево фиксированной
+// глубины по длине сообщения и первым токенам, в листьях — кластеры,
+// которые сливаются при достаточном сходстве.
+
+const (
+	Wildcard = "<*>"
+
+	drainDepth       = 4
+	drainSimilarity  = 0.5
+	drainMaxChildren = 100
+	drainMaxTokens   = 64
+)
+
+var maskRes = []*regexp.Regexp{
+	regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`),
+	regexp.MustCompile(`^(\d{1,3}\.){3}\d{1,3}(:\d+)?$`),
+	regexp.MustCompile(`^[0-9a-fA-F]{8,}$`),
+	regexp.MustCompile(`^[-+]?\d+([.,:]\d+)*(ms|s|m|h|µs|ns|B|KB|MB)?$`),
+	regexp.MustCompile(`^(/[^/\s]*){2,}$`),
+	regexp.MustCompile(`^[a-z]+-[a-z0-9]{10,}$`),
+}
+
+type MessageTemplate struct {
+	ID     string   `json:"id"`
+	Tokens []string `json:"tokens"`
+}
+
+func (t *MessageTemplate) String() string {
+	return strings.Join(t.Tokens, " ")
+}
+
+type drainNode struct {
+	children  map[string]*drainNode
+	templates []*MessageTemplate
+}
+
+type TemplateMiner struct {
+	root      *drainNode
+	templates map[string]*MessageTemplate
+	seq       int
+}
+
+func NewTemplateMiner() *TemplateMiner {
+	return &TemplateMiner{
+		root:      &drainNode{children: map[string]*drainNode{}},
+		templates: make(map[string]*MessageTemplate),
+	}
+}
+
+// Add относит сообщение к шаблону (создавая новый при необходимости) и
+// возвращает его ID.
+func (m *TemplateMiner) Add(message string) string {
+	tokens := TokenizeMessage(message)
+	leaf := m.leaf(tokens)
+	if t := bestTemplate(leaf.templates, tokens); t != nil {
+		for i := range t.Tokens {
+			if t.Tokens[i] != tokens[i] {
+				t.Tokens[i] = Wildcard
+			}
+		}
+		return t.ID
+	}
+	m.seq++
+	t := &MessageTemplate{ID: fmt.Sprintf("tpl-%d", m.seq), Tokens: tokens}
+	leaf.templates = append(leaf.templates, t)
+	m.templates[t.ID] = t
+	return t.ID
+}
+
+func (m *TemplateMiner) Get(id string) (*MessageTemplate, bool) {
+	t, ok := m.templates[id]
+	return t, ok
+}
+
+func (m *TemplateMiner) leaf(tokens []string) *drainNode {
+	node := child(m.root, fmt.Sprint(len(tokens)))
+	for i := 0; i < drainDepth-2 && i < len(tokens); i++ {
+		key := tokens[i]
+		if _, ok := node.children[key]; !ok && len(node.children) >= drainMaxChildren {
+			key = Wildcard
+		}
+		node = child(node, key)
+	}
+	return node
+}
+
+func child(n *drainNode, key string) *drainNode {
+	c, ok := n.children[key]
+	if !ok {
+		c = &drainNode{children: map[string]*drainNode{}}
+		n.children[key] = c
+	}
+	return c
+}
+
+func bestTemplate(templates []*MessageTemplate, tokens []string) *MessageTemplate {
+	var best *MessageTemplate
+	bestSim := -1.0
+	for _, t := range templates {
+		same := 0
+		for i, tok := range t.Tokens {
+			if tok == Wildcard || tok == tokens[i] {
+				same++
+			}
+		}
+		sim := 1.0
+		if len(tokens) > 0 {
+			sim = float64(same) / float64(len(tokens))
+		}
+		if sim > bestSim {
+			best, bestSim = t, sim
+		}
+	}
+	if bestSim < drainSimilarity {
+		return nil
+	}
+	return best
+}
+
+// TokenizeMessage разбивает первую строку сообщения на токены и заменяет
+// переменные части (UUID, числа, пути, идентификаторы) на <*>.
+func TokenizeMessage(message string) []string {
+	if i := strings.IndexByte(message, '\n'); i >= 0 {
+		message = message[:i]
+	}
+	fields := strings.Fields(message)
+	if len(fields) > drainMaxTokens {
+		fields = fields[:drainMaxTokens]
+	}
+	for i, f := range fields {
+		core := strings.Trim(f, `"'(),;[]{}`)
+		for _, re := range maskRes {
+			if core != "" && re.MatchString(core) {
+				fields[i] = Wildcard
+				break
+			}
+		}
+	}
+	return fields
+}
+
+// templateMinerState — сериализуемое состояние майнера для снимков:
+// дерево хранит ID шаблонов, сами шаблоны — в Templates.
+type templateMinerState struct {
+	Seq       int                `json:"seq"`
+	Templates []*MessageTemplate `json:"templates"`
+	Root      *drainNodeState    `json:"root"`
+}
+
+type drainNodeState struct {
+	Children  map[string]*drainNodeState `json:"children,omitempty"`
+	Templates []string                   `json:"templates,omitempty"`
+}
+
+func (m *TemplateMiner) MarshalJSON() ([]byte, error) {
+	state := templateMinerState{Seq: m.seq, Templates: []*MessageTemplate{}}
+	for _, t := range m.templates {
+		state.Templates = append(state.Templates, t)
+	}
+	sort.Slice(state.Templates, func(i, j int) bool { return state.Templates[i].ID < state.Templates[j].ID })
+	var walk func(n *drainNode) *drainNodeState
+	walk = func(n *drainNode) *drainNodeState {
+		s := &drainNodeState{}
+		for _, t := range n.templates {
+			s.Templates = append(s.Templates, t.ID)
+		}
+		if len(n.children) > 0 {
+			s.Children = make(map[string]*drainNodeState, len(n.children))
+			for key, c := range n.children {
+				s.Children[key] = walk(c)
+			}
+		}
+		return s
+	}
+	state.Root = walk(m.root)
+	return json.Marshal(state)
+}
+
+func (m *TemplateMiner) UnmarshalJSON(data []byte) error {
+	var state templateMinerState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	*m = *NewTemplateMiner()
+	m.seq = state.Seq
+	for _, t := range state.Templates {
+		m.templates[t.ID] = t
+	}
+	var walk func(s *drainNodeState) (*drainNode, error)
+	walk = func(s *drainNodeState) (*drainNode, error) {
+		n := &drainNode{children: map[string]*drainNode{}}
+		for _, id := range s.Templates {
+			t, ok := m.templates[id]
+			if !ok {
+				return nil, fmt.Errorf("unknown template %s", id)
+			}
+			n.templates = append(n.templates, t)
+		}
+		for key, cs := range s.Children {
+			c, err := walk(cs)
+			if err != nil {
+				return nil, err
+			}
+			n.children[key] = c
+		}
+		return n, nil
+	}
+	if state.Root == nil {
+		return nil
+	}
+	root, err := walk(state.Root)
+	if err != nil {
+		return err
+	}
+	m.root = root
+	return nil
+}
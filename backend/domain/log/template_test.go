@@ -0,0 +1,85 @@
+package log
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestTokenizeMessage(t *testing.T) {
+	tests := []struct {
+		message, want string
+	}{
+		{"Starting provider", "Starting provider"},
+		{"vip 5f0c3c1e-6a2b-4c3d-9e8f-0123456789ab created", "vip <*> created"},
+		{"dial tcp 10.0.0.1:443, timeout", "dial tcp <*> timeout"},
+		{"took 150ms, retry 3", "took <*> retry <*>"},
+		{"GET /api/v1/networks done", "GET <*> done"},
+		{"instance vm-abcdef123456 ready", "instance <*> ready"},
+		{`id "deadbeef01" (hex)`, "id <*> (hex)"},
+		{"first line\nsecond line 42", "first line"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(TokenizeMessage(tt.message), " "); got != tt.want {
+			t.Errorf("TokenizeMessage(%q) = %q, want %q", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestTemplateMinerAdd(t *testing.T) {
+	m := NewTemplateMiner()
+	a := m.Add("Creating resource t1_vpc_network name=office")
+	b := m.Add("Creating resource t1_vpc_network name=home")
+	if a != b {
+		t.Fatalf("similar messages got templates %s and %s", a, b)
+	}
+	tpl, ok := m.Get(a)
+	if !ok {
+		t.Fatalf("Get(%s): not found", a)
+	}
+	if got := tpl.String(); got != "Creating resource t1_vpc_network <*>" {
+		t.Errorf("merged template = %q", got)
+	}
+
+	if c := m.Add("Creating resource t1_vpc_network name=office region=ru"); c == a {
+		t.Error("message of another length joined the template")
+	}
+	if d := m.Add("Creating provider plugin process now"); d == a {
+		t.Error("dissimilar message joined the template")
+	}
+	if e := m.Add("Creating resource t1_vpc_network 42"); e != a {
+		t.Errorf("message matching the wildcard got template %s, want %s", e, a)
+	}
+	if _, ok := m.Get("tpl-100"); ok {
+		t.Error("Get of unknown ID succeeded")
+	}
+}
+
+func TestTemplateMinerJSON(t *testing.T) {
+	m := NewTemplateMiner()
+	first := m.Add("Refreshing state id=1")
+	m.Add("Refreshing state id=2")
+	other := m.Add("Apply complete! Resources: 3 added")
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := NewTemplateMiner()
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatal(err)
+	}
+	if got := restored.Add("Refreshing state id=3"); got != first {
+		t.Errorf("after restore message got template %s, want %s", got, first)
+	}
+	if got := restored.Add("Apply complete! Resources: 5 added"); got != other {
+		t.Errorf("after restore message got template %s, want %s", got, other)
+	}
+	if got := restored.Add("Plan: 1 to add"); got != "tpl-3" {
+		t.Errorf("new template after restore = %s, want tpl-3", got)
+	}
+	if err := json.Unmarshal([]byte(`{"root":{"templates":["tpl-9"]}}`), restored); err == nil {
+		t.Error("unknown template in tree: no error")
+	}
+}
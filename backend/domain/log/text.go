@@ -0,0 +1,305 @@
+package log
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Формат файла логов: TF_LOG_FORMAT=json или текстовый вывод hclog
+// (TF_LOG без JSON).
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// textHeaderRe — первая строка записи hclog:
+//
+//	2025-09-09T10:55:44.443+0300 [DEBUG] provider.terraform-provider-t1cloud: Sending HTTP Request: tf_rpc=ReadResource
+var textHeaderRe = regexp.MustCompile(
+	`^(\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:Z|[+-]\d{2}:?\d{2}))\s+\[(TRACE|DEBUG|INFO|WARN|ERROR)\]\s*(.*)$`)
+
+// textModuleRe — имя логгера перед сообщением: provider, provider.stdio,
+// backend/local. Слова с заглавной буквы (Error:) модулем не считаются.
+var textModuleRe = regexp.MustCompile(`^([a-z][a-z0-9_.\-/]*): `)
+
+var textKeyRe = regexp.MustCompile(`^[A-Za-z0-9_@][A-Za-z0-9_@.\-]*$`)
+
+// Формат @timestamp в JSON-логах Terraform
+const jsonTimestampLayout = "2006-01-02T15:04:05.000000Z07:00"
+
+const detectLines = 50
+
+// DetectFormat определяет формат по первым непустым строкам: текстовый,
+// если строк-заголовков hclog больше, чем JSON-объектов.
+func DetectFormat(head []byte) string {
+	var jsonLines, textLines, n int
+	for line := range bytes.Lines(head) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		switch {
+		case line[0] == '{':
+			jsonLines++
+		case textHeaderRe.Match(line):
+			textLines++
+		}
+		if n++; n == detectLines {
+			break
+		}
+	}
+	if textLines > jsonLines {
+		return FormatText
+	}
+	return FormatJSON
+}
+
+type textEntry struct {
+	lineNo int
+	offset int64
+	raw    bytes.Buffer
+	header []string // ts, level, остаток строки
+	cont   []string
+}
+
+// loadText разбирает текстовый вывод hclog. Строки без заголовка —
+// продолжение предыдущей записи; строки до первой записи попадают в
+// повреждённые.
+func loadText(scan *bufio.Scanner, lineStart *int64) ([]Log, []CorruptedLine, error) {
+	var (
+		logs      []Log
+		corrupted []CorruptedLine
+		cur       *textEntry
+	)
+	flush := func() {
+		if cur == nil {
+			return
+		}
+		log := parseTextEntry(cur.header, cur.cont)
+		log.Id = LineID(cur.raw.Bytes(), cur.lineNo)
+		log.LineNo = cur.lineNo
+		log.ByteOffset = cur.offset
+		logs = append(logs, log)
+		cur = nil
+	}
+	lineNo := 0
+	for scan.Scan() {
+		lineNo++
+		raw := scan.Bytes()
+		if m := textHeaderRe.FindSubmatch(raw); m != nil {
+			flush()
+			cur = &textEntry{
+				lineNo: lineNo,
+				offset: *lineStart,
+				header: []string{string(m[1]), string(m[2]), string(m[3])},
+			}
+			cur.raw.Write(raw)
+			continue
+		}
+		if cur == nil {
+			if len(bytes.TrimSpace(raw)) == 0 {
+				continue
+			}
+			corrupted = append(corrupted, CorruptedLine{
+				ID:         LineID(raw, lineNo),
+				Line:       lineNo,
+				Offset:     *lineStart,
+				Original:   string(raw),
+				ParseError: "text log: line does not start with a timestamp and level",
+			})
+			continue
+		}
+		cur.raw.WriteByte('\n')
+		cur.raw.Write(raw)
+		cur.cont = append(cur.cont, string(raw))
+	}
+	flush()
+	return logs, corrupted, scan.Err()
+}
+
+// parseTextEntry собирает запись из заголовка и строк продолжения.
+// Атрибуты key=value превращаются в поля JSON-записи с теми же именами,
+// так что дальше текстовые логи обрабатываются так же, как JSON.
+func parseTextEntry(header, cont []string) Log {
+	fields := map[string]any{
+		"@level": strings.ToLower(header[1]),
+	}
+	fields["@timestamp"] = header[0]
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, header[0]); err == nil {
+			fields["@timestamp"] = t.Format(jsonTimestampLayout)
+			break
+		}
+	}
+
+	rest := header[2]
+	if m := textModuleRe.FindStringSubmatch(rest); m != nil {
+		fields["@module"] = m[1]
+		rest = rest[len(m[0]):]
+	}
+	msg, attrs := splitTextAttrs(rest)
+	message := []string{msg}
+
+	// Многострочное значение hclog: "key=" и строки "  | ..."
+	var multiKey string
+	var multi []string
+	endMulti := func() {
+		if multiKey != "" {
+			attrs = append(attrs, [2]string{multiKey, strings.Join(multi, "\n")})
+		}
+		multiKey, multi = "", nil
+	}
+	for _, line := range cont {
+		trimmed := strings.TrimLeft(line, " \t")
+		if multiKey != "" && (trimmed == "|" || strings.HasPrefix(trimmed, "| ")) {
+			multi = append(multi, strings.TrimPrefix(strings.TrimPrefix(trimmed, "|"), " "))
+			continue
+		}
+		endMulti()
+		if key, ok := strings.CutSuffix(trimmed, "="); ok && textKeyRe.MatchString(key) {
+			multiKey = key
+			continue
+		}
+		if kv, ok := parseTextAttrs(trimmed); ok && trimmed != "" {
+			attrs = append(attrs, kv...)
+			continue
+		}
+		message = append(message, line)
+	}
+	endMulti()
+	fields["@message"] = strings.TrimRight(strings.Join(message, "\n"), "\n ")
+
+	kinds := logFieldKinds()
+	for _, kv := range attrs {
+		kind, ok := kinds[kv[0]]
+		if !ok {
+			continue
+		}
+		if v, ok := textValue(kind, kv[1]); ok {
+			fields[kv[0]] = v
+		}
+	}
+	raw, _ := json.Marshal(fields)
+	log, err := ParseLine(raw)
+	if err != nil {
+		// Значения уже приведены к типам полей, сюда попадать не должны
+		log = Log{At_timestamp: header[0], At_level: strings.ToLower(header[1]), At_message: header[2]}
+	}
+	return log
+}
+
+// splitTextAttrs отделяет от сообщения хвост ": key=value ...". Берётся
+// самое левое разделение, после которого строка целиком — атрибуты.
+func splitTextAttrs(s string) (string, [][2]string) {
+	for i := 0; ; {
+		j := strings.Index(s[i:], ": ")
+		if j < 0 {
+			break
+		}
+		j += i
+		if kv, ok := parseTextAttrs(s[j+2:]); ok && len(kv) > 0 {
+			return s[:j], kv
+		}
+		i = j + 2
+	}
+	// Сообщение без атрибутов, но с завершающим двоеточием перед
+	// многострочным значением
+	return strings.TrimSuffix(s, ":"), nil
+}
+
+// parseTextAttrs разбирает строку вида a=1 b="x y" c= целиком; значение
+// в кавычках — строка Go.
+func parseTextAttrs(s string) ([][2]string, bool) {
+	var kv [][2]string
+	s = strings.TrimSpace(s)
+	for s != "" {
+		eq := strings.IndexByte(s, '=')
+		if eq <= 0 || !textKeyRe.MatchString(s[:eq]) {
+			return nil, false
+		}
+		key := s[:eq]
+		s = s[eq+1:]
+		var value string
+		if strings.HasPrefix(s, `"`) {
+			quoted, err := strconv.QuotedPrefix(s)
+			if err != nil {
+				return nil, false
+			}
+			value, _ = strconv.Unquote(quoted)
+			s = s[len(quoted):]
+			if s != "" && s[0] != ' ' {
+				return nil, false
+			}
+		} else {
+			end := strings.IndexByte(s, ' ')
+			if end < 0 {
+				end = len(s)
+			}
+			value, s = s[:end], s[end:]
+		}
+		kv = append(kv, [2]string{key, value})
+		s = strings.TrimLeft(s, " ")
+	}
+	return kv, true
+}
+
+var (
+	fieldKindsOnce sync.Once
+	fieldKinds     map[string]reflect.Type
+)
+
+// logFieldKinds — типы полей Log по JSON-именам. Служебные поля (состояние
+// разбора, триаж, метки) из текста не заполняются.
+func logFieldKinds() map[string]reflect.Type {
+	fieldKindsOnce.Do(func() {
+		fieldKinds = make(map[string]reflect.Type)
+		t := reflect.TypeFor[Log]()
+		for i := range t.NumField() {
+			f := t.Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+			switch f.Type.Kind() {
+			case reflect.Pointer, reflect.Map, reflect.Struct:
+				continue
+			}
+			fieldKinds[name] = f.Type
+		}
+		for _, name := range []string{"id", "read", "repaired", "state", "assignee", "template_id", "upload_id", "line_no", "byte_offset", "state_addresses"} {
+			delete(fieldKinds, name)
+		}
+	})
+	return fieldKinds
+}
+
+// textValue приводит значение атрибута к типу поля.
+func textValue(t reflect.Type, s string) (any, bool) {
+	switch t.Kind() {
+	case reflect.String, reflect.Interface:
+		return s, true
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, 64)
+		return n, err == nil
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		return f, err == nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		return b, err == nil
+	case reflect.Slice:
+		// hclog печатает []string как [a b c]
+		if t.Elem().Kind() != reflect.String {
+			return nil, false
+		}
+		return strings.Fields(strings.TrimSuffix(strings.TrimPrefix(s, "["), "]")), true
+	}
+	return nil, false
+}
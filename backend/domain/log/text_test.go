@@ -0,0 +1,185 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDetectFormat(t *testing.T) {
+	const (
+		jsonLine = `{"@level":"info","@message":"Starting"}`
+		textLine = "2025-09-09T10:55:44.443+0300 [INFO]  Terraform version: 1.13.1"
+	)
+	tests := []struct {
+		name, head, want string
+	}{
+		{"json", jsonLine + "\n" + jsonLine, FormatJSON},
+		{"text", textLine + "\n" + textLine, FormatText},
+		{"text with continuations", textLine + "\n  | body\nplain line\n" + textLine, FormatText},
+		{"mostly json", jsonLine + "\n" + textLine + "\n" + jsonLine, FormatJSON},
+		{"mostly text", textLine + "\n" + jsonLine + "\n" + textLine, FormatText},
+		{"tie is json", textLine + "\n" + jsonLine, FormatJSON},
+		{"blank lines skipped", "\n\n   \n" + textLine, FormatText},
+		{"empty", "", FormatJSON},
+		{"Z timestamp", "2025-09-09T07:55:44Z [DEBUG] x", FormatText},
+		{"no level", "2025-09-09T10:55:44.443+0300 Starting", FormatJSON},
+	}
+	for _, tt := range tests {
+		if got := DetectFormat([]byte(tt.head)); got != tt.want {
+			t.Errorf("%s: DetectFormat = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	// Решают только первые detectLines непустых строк
+	head := strings.Repeat(jsonLine+"\n", detectLines) + strings.Repeat(textLine+"\n", detectLines+1)
+	if got := DetectFormat([]byte(head)); got != FormatJSON {
+		t.Errorf("DetectFormat looked past %d lines: %s", detectLines, got)
+	}
+}
+
+// parseText разбирает запись из строк в формате hclog.
+func parseText(t *testing.T, lines ...string) Log {
+	t.Helper()
+	m := textHeaderRe.FindStringSubmatch(lines[0])
+	if m == nil {
+		t.Fatalf("not a header: %q", lines[0])
+	}
+	return parseTextEntry(m[1:], lines[1:])
+}
+
+func TestParseTextEntryHeader(t *testing.T) {
+	l := parseText(t, `2025-09-09T10:55:44.443+0300 [DEBUG] provider.terraform-provider-t1cloud: Sending HTTP Request: tf_rpc=ReadResource tf_http_req_method=GET tf_http_res_status_code=200`)
+	got := fmt.Sprintf("%s|%s|%s|%s|%s|%s|%d", l.At_timestamp, l.At_level, l.At_module, l.At_message, l.Tf_rpc, l.Tf_http_req_method, l.Tf_http_res_status_code)
+	want := "2025-09-09T10:55:44.443000+03:00|debug|provider.terraform-provider-t1cloud|Sending HTTP Request|ReadResource|GET|200"
+	if got != want {
+		t.Errorf("parsed %s\nwant   %s", got, want)
+	}
+}
+
+func TestParseTextEntry(t *testing.T) {
+	const ts = "2025-09-09T10:55:44.443+0300 "
+	tests := []struct {
+		name    string
+		lines   []string
+		message string
+		check   func(Log) string
+		want    string
+	}{
+		{
+			name:    "no module, capitalized prefix is message",
+			lines:   []string{ts + "[ERROR] Error: quota exceeded"},
+			message: "Error: quota exceeded",
+		},
+		{
+			name:    "colon without attributes",
+			lines:   []string{ts + "[INFO]  backend/local: apply: waiting for lock: state.tflock"},
+			message: "apply: waiting for lock: state.tflock",
+			check:   func(l Log) string { return l.At_module },
+			want:    "backend/local",
+		},
+		{
+			name:    "leftmost split that leaves only attributes",
+			lines:   []string{ts + "[DEBUG] provider: Call failed: retry: tf_rpc=ApplyResourceChange"},
+			message: "Call failed: retry",
+			check:   func(l Log) string { return l.Tf_rpc },
+			want:    "ApplyResourceChange",
+		},
+		{
+			name:    "quoted value with spaces and escapes",
+			lines:   []string{ts + `[WARN]  provider: Diagnostic: diagnostic_summary="bad \"name\" value" diagnostic_error_count=2`},
+			message: "Diagnostic",
+			check:   func(l Log) string { return fmt.Sprint(l.Diagnostic_summary, "/", l.Diagnostic_error_count) },
+			want:    `bad "name" value/2`,
+		},
+		{
+			name:    "broken quote keeps message",
+			lines:   []string{ts + `[WARN]  provider: Diagnostic: diagnostic_summary="open`},
+			message: `Diagnostic: diagnostic_summary="open`,
+		},
+		{
+			name:    "unknown and mistyped attributes dropped",
+			lines:   []string{ts + "[DEBUG] provider: Response: nope=1 tf_http_res_status_code=OK tf_rpc=ReadResource"},
+			message: "Response",
+			check:   func(l Log) string { return fmt.Sprint(l.Tf_http_res_status_code, "/", l.Tf_rpc) },
+			want:    "0/ReadResource",
+		},
+		{
+			name: "multi-line value",
+			lines: []string{
+				ts + "[DEBUG] provider: Received HTTP Response: tf_rpc=ReadResource",
+				"  tf_http_res_body=",
+				`  | {"id": "n-1",`,
+				"  |",
+				`  |  "name": "office"}`,
+				"  tf_http_res_status_code=200",
+			},
+			message: "Received HTTP Response",
+			check: func(l Log) string {
+				return fmt.Sprintf("%q/%d", l.Tf_http_res_body, l.Tf_http_res_status_code)
+			},
+			want: `"{\"id\": \"n-1\",\n\n \"name\": \"office\"}"/200`,
+		},
+		{
+			name: "multi-line value after message with trailing colon",
+			lines: []string{
+				ts + "[DEBUG] provider: Received HTTP Response:",
+				"  tf_http_res_body=",
+				"  | line one",
+				"  | line two",
+			},
+			message: "Received HTTP Response",
+			check:   func(l Log) string { return fmt.Sprintf("%q", l.Tf_http_res_body) },
+			want:    `"line one\nline two"`,
+		},
+		{
+			name: "plain continuation lines join the message",
+			lines: []string{
+				ts + "[ERROR] provider: Error: creating network",
+				"",
+				"  with t1_vpc_network.office,",
+				"  on main.tf line 3",
+				"",
+			},
+			message: "Error: creating network\n\n  with t1_vpc_network.office,\n  on main.tf line 3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := parseText(t, tt.lines...)
+			if l.At_message != tt.message {
+				t.Errorf("message = %q, want %q", l.At_message, tt.message)
+			}
+			if tt.check != nil {
+				if got := tt.check(l); got != tt.want {
+					t.Errorf("got %s, want %s", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitTextAttrs(t *testing.T) {
+	tests := []struct {
+		in, msg, attrs string
+	}{
+		{"Starting provider", "Starting provider", ""},
+		{"Waiting:", "Waiting", ""},
+		{"Sending: a=1 b=2", "Sending", "a=1 b=2"},
+		{"a: b: c=3", "a: b", "c=3"},
+		{"url: http://x?a=1", "url: http://x?a=1", ""},
+		{`msg: k="x y" e=`, "msg", `k=x y e=`},
+		{"msg: k=1 not-an-attr", "msg: k=1 not-an-attr", ""},
+		{"msg: =1", "msg: =1", ""},
+	}
+	for _, tt := range tests {
+		msg, kv := splitTextAttrs(tt.in)
+		var attrs []string
+		for _, p := range kv {
+			attrs = append(attrs, p[0]+"="+p[1])
+		}
+		if msg != tt.msg || strings.Join(attrs, " ") != tt.attrs {
+			t.Errorf("splitTextAttrs(%q) = %q, %q; want %q, %q", tt.in, msg, strings.Join(attrs, " "), tt.msg, tt.attrs)
+		}
+	}
+}
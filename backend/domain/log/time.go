@@ -0,0 +1,24 @@
+package log
+
+import "time"
+
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.000-0700",
+	"2006-01-02T15:04:05.000Z0700",
+}
+
+// Time возвращает момент записи: @timestamp, а если его нет — timestamp.
+func Time(l Log) (time.Time, bool) {
+	for _, ts := range []string{l.At_timestamp, l.Timestamp} {
+		if ts == "" {
+			continue
+		}
+		for _, layout := range timestampLayouts {
+			if t, err := time.Parse(layout, ts); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,60 @@
+package log
+
+import "time"
+
+type TriageState string
+
+const (
+	StateUnread        TriageState = "unread"
+	StateRead          TriageState = "read"
+	StateInvestigating TriageState = "investigating"
+	StateResolved      TriageState = "resolved"
+	StateIgnored       TriageState = "ignored"
+)
+
+func (s TriageState) Valid() bool {
+	switch s {
+	case StateUnread, StateRead, StateInvestigating, StateResolved, StateIgnored:
+		return true
+	}
+	return false
+}
+
+// SetState выставляет состояние разбора и синхронизирует флаг Read.
+func (l *Log) SetState(s TriageState) {
+	l.State = s
+	l.Read = s != StateUnread
+}
+
+func (l *Log) TriageState() TriageState {
+	if l.State == "" {
+		return StateUnread
+	}
+	return l.State
+}
+
+// TriageUpdate — изменение состояния; nil-поля не меняются.
+type TriageUpdate struct {
+	State    *TriageState `json:"state,omitempty"`
+	Assignee *string      `json:"assignee,omitempty"`
+}
+
+type NoteTargetKind string
+
+const (
+	NoteTargetLog   NoteTargetKind = "log"
+	NoteTargetGroup NoteTargetKind = "group"
+)
+
+type NoteTarget struct {
+	Kind NoteTargetKind `json:"kind"`
+	ID   string         `json:"id"`
+}
+
+type Note struct {
+	ID        string     `json:"id"`
+	Target    NoteTarget `json:"target"`
+	Author    string     `json:"author"`
+	Text      string     `json:"text"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -0,0 +1,367 @@
+package log
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Машиночитаемый вывод terraform plan/apply -json: записи с полем type
+// (version, planned_change, apply_start, apply_complete, apply_errored,
+// diagnostic, change_summary, outputs, ...) и @module terraform.ui.
+const (
+	UIVersion       = "version"
+	UIPlannedChange = "planned_change"
+	UIResourceDrift = "resource_drift"
+	UIRefreshStart  = "refresh_start"
+	UIRefreshDone   = "refresh_complete"
+	UIApplyStart    = "apply_start"
+	UIApplyProgress = "apply_progress"
+	UIApplyComplete = "apply_complete"
+	UIApplyErrored  = "apply_errored"
+	UIDiagnosticEv  = "diagnostic"
+	UIChangeSummary = "change_summary"
+	UIOutputs       = "outputs"
+)
+
+type UIResource struct {
+	Addr            string `json:"addr"`
+	Module          string `json:"module"`
+	Resource        string `json:"resource"`
+	ImpliedProvider string `json:"implied_provider"`
+	ResourceType    string `json:"resource_type"`
+	ResourceName    string `json:"resource_name"`
+	ResourceKey     any    `json:"resource_key"`
+}
+
+// UIHook — ход операции над ресурсом (apply_*, refresh_*, provision_*).
+type UIHook struct {
+	Resource       UIResource `json:"resource"`
+	Action         string     `json:"action,omitempty"`
+	IDKey          string     `json:"id_key,omitempty"`
+	IDValue        string     `json:"id_value,omitempty"`
+	ElapsedSeconds float64    `json:"elapsed_seconds,omitempty"`
+	Provisioner    string     `json:"provisioner,omitempty"`
+}
+
+// UIChange — запланированное изменение или дрейф ресурса.
+type UIChange struct {
+	Resource         UIResource  `json:"resource"`
+	PreviousResource *UIResource `json:"previous_resource,omitempty"`
+	Action           string      `json:"action"`
+	Reason           string      `json:"reason,omitempty"`
+}
+
+type UIChanges struct {
+	Add       int    `json:"add"`
+	Change    int    `json:"change"`
+	Import    int    `json:"import"`
+	Remove    int    `json:"remove"`
+	Operation string `json:"operation"` // plan, apply, destroy
+}
+
+type UIDiagnostic struct {
+	Severity string   `json:"severity"`
+	Summary  string   `json:"summary"`
+	Detail   string   `json:"detail,omitempty"`
+	Address  string   `json:"address,omitempty"`
+	Range    *UIRange `json:"range,omitempty"`
+}
+
+type UIRange struct {
+	Filename string `json:"filename"`
+	Start    UIPos  `json:"start"`
+	End      UIPos  `json:"end"`
+}
+
+type UIPos struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	Byte   int `json:"byte"`
+}
+
+type UIOutput struct {
+	Sensitive bool   `json:"sensitive"`
+	Type      any    `json:"type,omitempty"`
+	Value     any    `json:"value,omitempty"`
+	Action    string `json:"action,omitempty"`
+}
+
+// IsUIEvent — запись машиночитаемого вывода terraform -json.
+func IsUIEvent(l Log) bool {
+	return l.Type != ""
+}
+
+// UIAddress — адрес ресурса события terraform -json.
+func UIAddress(l Log) string {
+	switch {
+	case l.Hook != nil:
+		return l.Hook.Resource.Addr
+	case l.Change != nil:
+		return l.Change.Resource.Addr
+	case l.Diagnostic != nil:
+		return l.Diagnostic.Address
+	}
+	return ""
+}
+
+func uiAction(l *Log) string {
+	switch {
+	case l.Hook != nil:
+		return l.Hook.Action
+	case l.Change != nil:
+		return l.Change.Action
+	}
+	return ""
+}
+
+// applyUIEvent переносит диагностику и тип ресурса события в поля
+// TF_LOG, чтобы фильтры, ошибки и gate работали для обоих форматов.
+func applyUIEvent(l *Log) {
+	if d := l.Diagnostic; d != nil && l.Diagnostic_summary == "" {
+		l.Diagnostic_severity = d.Severity
+		l.Diagnostic_summary = d.Summary
+		l.Diagnostic_detail = d.Detail
+	}
+	var res *UIResource
+	switch {
+	case l.Hook != nil:
+		res = &l.Hook.Resource
+	case l.Change != nil:
+		res = &l.Change.Resource
+	}
+	if res == nil || res.ResourceType == "" || ResourceType(*l) != "" {
+		return
+	}
+	if strings.HasPrefix(res.Resource, "data.") {
+		l.Tf_data_source_type = res.ResourceType
+	} else {
+		l.Tf_resource_type = res.ResourceType
+	}
+}
+
+// UIResourceOutcome — итог по ресурсу: план, применение, время и вызовы
+// провайдера из TF_LOG того же запуска.
+type UIResourceOutcome struct {
+	Address        string     `json:"address"`
+	ResourceType   string     `json:"resource_type,omitempty"`
+	Planned        string     `json:"planned,omitempty"` // действие плана
+	Reason         string     `json:"reason,omitempty"`
+	Drift          string     `json:"drift,omitempty"`
+	Action         string     `json:"action,omitempty"` // действие применения
+	Status         string     `json:"status"`           // planned, refreshed, applying, complete, errored
+	IDKey          string     `json:"id_key,omitempty"`
+	IDValue        string     `json:"id_value,omitempty"`
+	ElapsedSeconds float64    `json:"elapsed_seconds"`
+	Start          *time.Time `json:"start,omitempty"`
+	End            *time.Time `json:"end,omitempty"`
+	Diagnostics    []string   `json:"diagnostics"`
+	RPCs           []RPCCall  `json:"rpcs"`
+}
+
+type UIChangeSummaryEntry struct {
+	Timestamp string `json:"timestamp"`
+	Message   string `json:"message"`
+	UIChanges
+}
+
+type UIDiagnosticEntry struct {
+	Timestamp string `json:"timestamp"`
+	LogID     string `json:"log_id"`
+	UIDiagnostic
+}
+
+type UIReport struct {
+	UploadID      string                 `json:"upload_id"`
+	TraceUploadID string                 `json:"trace_upload_id,omitempty"`
+	Terraform     string                 `json:"terraform,omitempty"`
+	Start         *time.Time             `json:"start,omitempty"`
+	End           *time.Time             `json:"end,omitempty"`
+	Events        map[string]int         `json:"events"` // число событий по type
+	Resources     []UIResourceOutcome    `json:"resources"`
+	Summaries     []UIChangeSummaryEntry `json:"change_summaries"`
+	Diagnostics   []UIDiagnosticEntry    `json:"diagnostics"`
+	Outputs       map[string]UIOutput    `json:"outputs,omitempty"`
+}
+
+// BuildUIReport собирает события terraform -json загрузки; записи TF_LOG
+// пропускаются.
+func BuildUIReport(logs []Log) UIReport {
+	report := UIReport{
+		Events:      make(map[string]int),
+		Resources:   []UIResourceOutcome{},
+		Summaries:   []UIChangeSummaryEntry{},
+		Diagnostics: []UIDiagnosticEntry{},
+	}
+	byAddr := make(map[string]*UIResourceOutcome)
+	outcome := func(res UIResource) *UIResourceOutcome {
+		o, ok := byAddr[res.Addr]
+		if !ok {
+			o = &UIResourceOutcome{
+				Address:      res.Addr,
+				ResourceType: res.ResourceType,
+				Diagnostics:  []string{},
+				RPCs:         []RPCCall{},
+			}
+			byAddr[res.Addr] = o
+		}
+		if o.ResourceType == "" {
+			o.ResourceType = res.ResourceType
+		}
+		return o
+	}
+	for _, l := range logs {
+		if !IsUIEvent(l) {
+			continue
+		}
+		report.Events[l.Type]++
+		at, ok := Time(l)
+		if ok {
+			if report.Start == nil || at.Before(*report.Start) {
+				report.Start = &at
+			}
+			if report.End == nil || at.After(*report.End) {
+				report.End = &at
+			}
+		}
+		switch l.Type {
+		case UIVersion:
+			report.Terraform = l.Terraform
+		case UIPlannedChange, UIResourceDrift:
+			if l.Change == nil {
+				continue
+			}
+			o := outcome(l.Change.Resource)
+			if l.Type == UIResourceDrift {
+				o.Drift = l.Change.Action
+				continue
+			}
+			o.Planned, o.Reason = l.Change.Action, l.Change.Reason
+			if o.Status == "" {
+				o.Status = "planned"
+			}
+		case UIChangeSummary:
+			if l.Changes != nil {
+				report.Summaries = append(report.Summaries, UIChangeSummaryEntry{
+					Timestamp: l.At_timestamp,
+					Message:   l.At_message,
+					UIChanges: *l.Changes,
+				})
+			}
+		case UIDiagnosticEv:
+			if l.Diagnostic == nil {
+				continue
+			}
+			report.Diagnostics = append(report.Diagnostics, UIDiagnosticEntry{
+				Timestamp:    l.At_timestamp,
+				LogID:        l.Id,
+				UIDiagnostic: *l.Diagnostic,
+			})
+			if addr := l.Diagnostic.Address; addr != "" {
+				o := outcome(UIResource{Addr: addr})
+				o.Diagnostics = append(o.Diagnostics, l.Diagnostic.Summary)
+			}
+		case UIOutputs:
+			report.Outputs = l.Outputs
+		}
+		if l.Hook == nil || l.Hook.Resource.Addr == "" {
+			continue
+		}
+		o := outcome(l.Hook.Resource)
+		if l.Hook.IDValue != "" {
+			o.IDKey, o.IDValue = l.Hook.IDKey, l.Hook.IDValue
+		}
+		if l.Hook.ElapsedSeconds > o.ElapsedSeconds {
+			o.ElapsedSeconds = l.Hook.ElapsedSeconds
+		}
+		switch l.Type {
+		case UIRefreshStart:
+			if o.Status == "" || o.Status == "planned" {
+				o.Status = "refreshing"
+			}
+			if ok && o.Start == nil {
+				o.Start = &at
+			}
+		case UIApplyStart:
+			o.Action, o.Status, o.End = l.Hook.Action, "applying", nil
+			if ok {
+				o.Start = &at
+			}
+		case UIRefreshDone:
+			if o.Status == "refreshing" {
+				o.Status = "refreshed"
+				if ok {
+					o.End = &at
+				}
+			}
+		case UIApplyComplete, UIApplyErrored:
+			o.Action = l.Hook.Action
+			o.Status = "complete"
+			if l.Type == UIApplyErrored {
+				o.Status = "errored"
+			}
+			if ok {
+				o.End = &at
+			}
+		}
+	}
+	for _, o := range byAddr {
+		if o.Status == "" {
+			o.Status = "unknown"
+		}
+		report.Resources = append(report.Resources, *o)
+	}
+	sort.Slice(report.Resources, func(i, j int) bool {
+		return report.Resources[i].Address < report.Resources[j].Address
+	})
+	return report
+}
+
+// uiCorrelationSlack — допуск при сопоставлении времени: события -json
+// пишет terraform, а вызовы — провайдер, в тексте TF_LOG время до мс.
+const uiCorrelationSlack = 100 * time.Millisecond
+
+// CorrelateUI находит для ресурсов отчёта вызовы провайдера в записях
+// TF_LOG того же запуска: тот же ресурс и время внутри операции (или
+// всего вывода -json, если время операции неизвестно).
+func CorrelateUI(report *UIReport, trace []Log) {
+	if report.Start == nil {
+		return
+	}
+	calls := RPCCalls(trace)
+	for i := range report.Resources {
+		o := &report.Resources[i]
+		// Для применённых ресурсов — окно apply, иначе весь вывод (план
+		// и чтение идут до событий planned_change)
+		from, to := *report.Start, *report.End
+		if o.Action != "" && o.Start != nil {
+			from = *o.Start
+			if o.End != nil {
+				to = *o.End
+			}
+		}
+		from, to = from.Add(-uiCorrelationSlack), to.Add(uiCorrelationSlack)
+		for _, call := range calls {
+			if !uiResourceMatch(*o, call.Resource) || call.End.Before(from) || call.Start.After(to) {
+				continue
+			}
+			o.RPCs = append(o.RPCs, call)
+		}
+	}
+}
+
+// uiResourceMatch сравнивает адрес из -json с ресурсом вызова: адрес без
+// модуля или только тип, если ресурсов этого типа несколько.
+func uiResourceMatch(o UIResourceOutcome, resource string) bool {
+	if resource == "" {
+		return false
+	}
+	if resource == o.Address || strings.HasSuffix(o.Address, "."+resource) {
+		return true
+	}
+	typ := o.ResourceType
+	if strings.Contains(o.Address, "data.") {
+		typ = "data." + typ
+	}
+	return o.ResourceType != "" && resource == typ
+}
@@ -0,0 +1,41 @@
+package log
+
+import (
+	"regexp"
+	"strings"
+)
+
+var idSegmentRes = []*regexp.Regexp{
+	regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`),
+	regexp.MustCompile(`^\d+$`),
+	regexp.MustCompile(`^[0-9a-fA-F]{16,}$`),
+}
+
+// Префиксные ID (proj-e5auo52qoqe2s26) отличаются от слов пути
+// (network-interfaces) наличием цифры.
+var prefixedIDRe = regexp.MustCompile(`^[a-z]+-[a-z0-9]{10,}$`)
+
+func isIDSegment(seg string) bool {
+	for _, re := range idSegmentRes {
+		if re.MatchString(seg) {
+			return true
+		}
+	}
+	return prefixedIDRe.MatchString(seg) && strings.ContainsAny(seg, "0123456789")
+}
+
+// NormalizeURI превращает URI запроса в шаблон: идентификаторы в пути
+// заменяются на {id}, query string отбрасывается.
+// /vpc/api/v1/projects/proj-xxx/networks/<uuid> -> /vpc/api/v1/projects/{id}/networks/{id}
+func NormalizeURI(uri string) string {
+	if i := strings.IndexAny(uri, "?#"); i >= 0 {
+		uri = uri[:i]
+	}
+	segments := strings.Split(uri, "/")
+	for i, seg := range segments {
+		if isIDSegment(seg) {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,47 @@
+package log
+
+import (
+	"context"
+	"regexp"
+	"time"
+)
+
+const DefaultWorkspace = "default"
+
+var workspaceIDRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// Quota — ограничения рабочего пространства; 0 означает «без ограничений».
+type Quota struct {
+	MaxLines       int   `json:"max_lines"`
+	MaxUploadBytes int64 `json:"max_upload_bytes"`
+}
+
+// Workspace — изолированное пространство команды: загрузки, записи,
+// битые строки, шаблоны, разбор и заметки видны только внутри него.
+type Workspace struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	Quota     Quota     `json:"quota"`
+	Lines     int       `json:"lines"`
+	Uploads   int       `json:"uploads"`
+}
+
+func ValidWorkspaceID(id string) bool {
+	return workspaceIDRe.MatchString(id)
+}
+
+type workspaceKey struct{}
+
+// WithWorkspace задаёт рабочее пространство, в котором выполняются методы Repo.
+func WithWorkspace(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, workspaceKey{}, id)
+}
+
+// WorkspaceFromContext возвращает рабочее пространство запроса, по умолчанию — default.
+func WorkspaceFromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(workspaceKey{}).(string); ok && id != "" {
+		return id
+	}
+	return DefaultWorkspace
+}
@@ -0,0 +1,592 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: tflogs/plugin/v1/plugin.proto
+
+// Протокол плагинов-обработчиков (hashicorp/go-plugin поверх gRPC). Записи
+// передаются в JSON — в том же виде, в каком их отдаёт REST API. Плагины
+// пишутся на Go через пакет plugin, на других языках — по этому файлу.
+
+package pluginv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type DescribeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DescribeRequest) Reset() {
+	*x = DescribeRequest{}
+	mi := &file_tflogs_plugin_v1_plugin_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DescribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DescribeRequest) ProtoMessage() {}
+
+func (x *DescribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tflogs_plugin_v1_plugin_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DescribeRequest.ProtoReflect.Descriptor instead.
+func (*DescribeRequest) Descriptor() ([]byte, []int) {
+	return file_tflogs_plugin_v1_plugin_proto_rawDescGZIP(), []int{0}
+}
+
+type ExportFormat struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Имя формата в запросе экспорта, например md.
+	Name        string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	ContentType string `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	// Расширение файла без точки.
+	Extension     string `protobuf:"bytes,3,opt,name=extension,proto3" json:"extension,omitempty"`
+	Description   string `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportFormat) Reset() {
+	*x = ExportFormat{}
+	mi := &file_tflogs_plugin_v1_plugin_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportFormat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportFormat) ProtoMessage() {}
+
+func (x *ExportFormat) ProtoReflect() protoreflect.Message {
+	mi := &file_tflogs_plugin_v1_plugin_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportFormat.ProtoReflect.Descriptor instead.
+func (*ExportFormat) Descriptor() ([]byte, []int) {
+	return file_tflogs_plugin_v1_plugin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ExportFormat) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ExportFormat) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *ExportFormat) GetExtension() string {
+	if x != nil {
+		return x.Extension
+	}
+	return ""
+}
+
+func (x *ExportFormat) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type DescribeResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Name             string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version          string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	OnEntry          bool                   `protobuf:"varint,3,opt,name=on_entry,json=onEntry,proto3" json:"on_entry,omitempty"`
+	OnUploadComplete bool                   `protobuf:"varint,4,opt,name=on_upload_complete,json=onUploadComplete,proto3" json:"on_upload_complete,omitempty"`
+	ExportFormats    []*ExportFormat        `protobuf:"bytes,5,rep,name=export_formats,json=exportFormats,proto3" json:"export_formats,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *DescribeResponse) Reset() {
+	*x = DescribeResponse{}
+	mi := &file_tflogs_plugin_v1_plugin_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DescribeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DescribeResponse) ProtoMessage() {}
+
+func (x *DescribeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tflogs_plugin_v1_plugin_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DescribeResponse.ProtoReflect.Descriptor instead.
+func (*DescribeResponse) Descriptor() ([]byte, []int) {
+	return file_tflogs_plugin_v1_plugin_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DescribeResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DescribeResponse) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *DescribeResponse) GetOnEntry() bool {
+	if x != nil {
+		return x.OnEntry
+	}
+	return false
+}
+
+func (x *DescribeResponse) GetOnUploadComplete() bool {
+	if x != nil {
+		return x.OnUploadComplete
+	}
+	return false
+}
+
+func (x *DescribeResponse) GetExportFormats() []*ExportFormat {
+	if x != nil {
+		return x.ExportFormats
+	}
+	return nil
+}
+
+type OnEntryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       [][]byte               `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OnEntryRequest) Reset() {
+	*x = OnEntryRequest{}
+	mi := &file_tflogs_plugin_v1_plugin_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OnEntryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OnEntryRequest) ProtoMessage() {}
+
+func (x *OnEntryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tflogs_plugin_v1_plugin_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OnEntryRequest.ProtoReflect.Descriptor instead.
+func (*OnEntryRequest) Descriptor() ([]byte, []int) {
+	return file_tflogs_plugin_v1_plugin_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *OnEntryRequest) GetEntries() [][]byte {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type OnEntryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       [][]byte               `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OnEntryResponse) Reset() {
+	*x = OnEntryResponse{}
+	mi := &file_tflogs_plugin_v1_plugin_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OnEntryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OnEntryResponse) ProtoMessage() {}
+
+func (x *OnEntryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tflogs_plugin_v1_plugin_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OnEntryResponse.ProtoReflect.Descriptor instead.
+func (*OnEntryResponse) Descriptor() ([]byte, []int) {
+	return file_tflogs_plugin_v1_plugin_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *OnEntryResponse) GetEntries() [][]byte {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type OnUploadCompleteRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Workspace string                 `protobuf:"bytes,1,opt,name=workspace,proto3" json:"workspace,omitempty"`
+	// log.Upload в JSON.
+	Upload []byte `protobuf:"bytes,2,opt,name=upload,proto3" json:"upload,omitempty"`
+	// log.RunSummary в JSON.
+	Summary       []byte `protobuf:"bytes,3,opt,name=summary,proto3" json:"summary,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OnUploadCompleteRequest) Reset() {
+	*x = OnUploadCompleteRequest{}
+	mi := &file_tflogs_plugin_v1_plugin_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OnUploadCompleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OnUploadCompleteRequest) ProtoMessage() {}
+
+func (x *OnUploadCompleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tflogs_plugin_v1_plugin_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OnUploadCompleteRequest.ProtoReflect.Descriptor instead.
+func (*OnUploadCompleteRequest) Descriptor() ([]byte, []int) {
+	return file_tflogs_plugin_v1_plugin_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *OnUploadCompleteRequest) GetWorkspace() string {
+	if x != nil {
+		return x.Workspace
+	}
+	return ""
+}
+
+func (x *OnUploadCompleteRequest) GetUpload() []byte {
+	if x != nil {
+		return x.Upload
+	}
+	return nil
+}
+
+func (x *OnUploadCompleteRequest) GetSummary() []byte {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+type OnUploadCompleteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OnUploadCompleteResponse) Reset() {
+	*x = OnUploadCompleteResponse{}
+	mi := &file_tflogs_plugin_v1_plugin_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OnUploadCompleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OnUploadCompleteResponse) ProtoMessage() {}
+
+func (x *OnUploadCompleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tflogs_plugin_v1_plugin_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OnUploadCompleteResponse.ProtoReflect.Descriptor instead.
+func (*OnUploadCompleteResponse) Descriptor() ([]byte, []int) {
+	return file_tflogs_plugin_v1_plugin_proto_rawDescGZIP(), []int{6}
+}
+
+type ExportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Format        string                 `protobuf:"bytes,1,opt,name=format,proto3" json:"format,omitempty"`
+	Entries       [][]byte               `protobuf:"bytes,2,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportRequest) Reset() {
+	*x = ExportRequest{}
+	mi := &file_tflogs_plugin_v1_plugin_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportRequest) ProtoMessage() {}
+
+func (x *ExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tflogs_plugin_v1_plugin_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportRequest.ProtoReflect.Descriptor instead.
+func (*ExportRequest) Descriptor() ([]byte, []int) {
+	return file_tflogs_plugin_v1_plugin_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ExportRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *ExportRequest) GetEntries() [][]byte {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type ExportResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportResponse) Reset() {
+	*x = ExportResponse{}
+	mi := &file_tflogs_plugin_v1_plugin_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportResponse) ProtoMessage() {}
+
+func (x *ExportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tflogs_plugin_v1_plugin_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportResponse.ProtoReflect.Descriptor instead.
+func (*ExportResponse) Descriptor() ([]byte, []int) {
+	return file_tflogs_plugin_v1_plugin_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ExportResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+var File_tflogs_plugin_v1_plugin_proto protoreflect.FileDescriptor
+
+const file_tflogs_plugin_v1_plugin_proto_rawDesc = "" +
+	"\n" +
+	"\x1dtflogs/plugin/v1/plugin.proto\x12\x10tflogs.plugin.v1\"\x11\n" +
+	"\x0fDescribeRequest\"\x85\x01\n" +
+	"\fExportFormat\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12!\n" +
+	"\fcontent_type\x18\x02 \x01(\tR\vcontentType\x12\x1c\n" +
+	"\textension\x18\x03 \x01(\tR\textension\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\"\xd0\x01\n" +
+	"\x10DescribeResponse\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\x12\x19\n" +
+	"\bon_entry\x18\x03 \x01(\bR\aonEntry\x12,\n" +
+	"\x12on_upload_complete\x18\x04 \x01(\bR\x10onUploadComplete\x12E\n" +
+	"\x0eexport_formats\x18\x05 \x03(\v2\x1e.tflogs.plugin.v1.ExportFormatR\rexportFormats\"*\n" +
+	"\x0eOnEntryRequest\x12\x18\n" +
+	"\aentries\x18\x01 \x03(\fR\aentries\"+\n" +
+	"\x0fOnEntryResponse\x12\x18\n" +
+	"\aentries\x18\x01 \x03(\fR\aentries\"i\n" +
+	"\x17OnUploadCompleteRequest\x12\x1c\n" +
+	"\tworkspace\x18\x01 \x01(\tR\tworkspace\x12\x16\n" +
+	"\x06upload\x18\x02 \x01(\fR\x06upload\x12\x18\n" +
+	"\asummary\x18\x03 \x01(\fR\asummary\"\x1a\n" +
+	"\x18OnUploadCompleteResponse\"A\n" +
+	"\rExportRequest\x12\x16\n" +
+	"\x06format\x18\x01 \x01(\tR\x06format\x12\x18\n" +
+	"\aentries\x18\x02 \x03(\fR\aentries\"$\n" +
+	"\x0eExportResponse\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data2\xed\x02\n" +
+	"\x10ProcessorService\x12Q\n" +
+	"\bDescribe\x12!.tflogs.plugin.v1.DescribeRequest\x1a\".tflogs.plugin.v1.DescribeResponse\x12N\n" +
+	"\aOnEntry\x12 .tflogs.plugin.v1.OnEntryRequest\x1a!.tflogs.plugin.v1.OnEntryResponse\x12i\n" +
+	"\x10OnUploadComplete\x12).tflogs.plugin.v1.OnUploadCompleteRequest\x1a*.tflogs.plugin.v1.OnUploadCompleteResponse\x12K\n" +
+	"\x06Export\x12\x1f.tflogs.plugin.v1.ExportRequest\x1a .tflogs.plugin.v1.ExportResponseBKZIgitlab.com/paradaise1/t1-hackaton-terraform/gen/tflogs/plugin/v1;pluginv1b\x06proto3"
+
+var (
+	file_tflogs_plugin_v1_plugin_proto_rawDescOnce sync.Once
+	file_tflogs_plugin_v1_plugin_proto_rawDescData []byte
+)
+
+func file_tflogs_plugin_v1_plugin_proto_rawDescGZIP() []byte {
+	file_tflogs_plugin_v1_plugin_proto_rawDescOnce.Do(func() {
+		file_tflogs_plugin_v1_plugin_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_tflogs_plugin_v1_plugin_proto_rawDesc), len(file_tflogs_plugin_v1_plugin_proto_rawDesc)))
+	})
+	return file_tflogs_plugin_v1_plugin_proto_rawDescData
+}
+
+var file_tflogs_plugin_v1_plugin_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_tflogs_plugin_v1_plugin_proto_goTypes = []any{
+	(*DescribeRequest)(nil),          // 0: tflogs.plugin.v1.DescribeRequest
+	(*ExportFormat)(nil),             // 1: tflogs.plugin.v1.ExportFormat
+	(*DescribeResponse)(nil),         // 2: tflogs.plugin.v1.DescribeResponse
+	(*OnEntryRequest)(nil),           // 3: tflogs.plugin.v1.OnEntryRequest
+	(*OnEntryResponse)(nil),          // 4: tflogs.plugin.v1.OnEntryResponse
+	(*OnUploadCompleteRequest)(nil),  // 5: tflogs.plugin.v1.OnUploadCompleteRequest
+	(*OnUploadCompleteResponse)(nil), // 6: tflogs.plugin.v1.OnUploadCompleteResponse
+	(*ExportRequest)(nil),            // 7: tflogs.plugin.v1.ExportRequest
+	(*ExportResponse)(nil),           // 8: tflogs.plugin.v1.ExportResponse
+}
+var file_tflogs_plugin_v1_plugin_proto_depIdxs = []int32{
+	1, // 0: tflogs.plugin.v1.DescribeResponse.export_formats:type_name -> tflogs.plugin.v1.ExportFormat
+	0, // 1: tflogs.plugin.v1.ProcessorService.Describe:input_type -> tflogs.plugin.v1.DescribeRequest
+	3, // 2: tflogs.plugin.v1.ProcessorService.OnEntry:input_type -> tflogs.plugin.v1.OnEntryRequest
+	5, // 3: tflogs.plugin.v1.ProcessorService.OnUploadComplete:input_type -> tflogs.plugin.v1.OnUploadCompleteRequest
+	7, // 4: tflogs.plugin.v1.ProcessorService.Export:input_type -> tflogs.plugin.v1.ExportRequest
+	2, // 5: tflogs.plugin.v1.ProcessorService.Describe:output_type -> tflogs.plugin.v1.DescribeResponse
+	4, // 6: tflogs.plugin.v1.ProcessorService.OnEntry:output_type -> tflogs.plugin.v1.OnEntryResponse
+	6, // 7: tflogs.plugin.v1.ProcessorService.OnUploadComplete:output_type -> tflogs.plugin.v1.OnUploadCompleteResponse
+	8, // 8: tflogs.plugin.v1.ProcessorService.Export:output_type -> tflogs.plugin.v1.ExportResponse
+	5, // [5:9] is the sub-list for method output_type
+	1, // [1:5] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_tflogs_plugin_v1_plugin_proto_init() }
+func file_tflogs_plugin_v1_plugin_proto_init() {
+	if File_tflogs_plugin_v1_plugin_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_tflogs_plugin_v1_plugin_proto_rawDesc), len(file_tflogs_plugin_v1_plugin_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_tflogs_plugin_v1_plugin_proto_goTypes,
+		DependencyIndexes: file_tflogs_plugin_v1_plugin_proto_depIdxs,
+		MessageInfos:      file_tflogs_plugin_v1_plugin_proto_msgTypes,
+	}.Build()
+	File_tflogs_plugin_v1_plugin_proto = out.File
+	file_tflogs_plugin_v1_plugin_proto_goTypes = nil
+	file_tflogs_plugin_v1_plugin_proto_depIdxs = nil
+}
@@ -0,0 +1,249 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: tflogs/plugin/v1/plugin.proto
+
+// Протокол плагинов-обработчиков (hashicorp/go-plugin поверх gRPC). Записи
+// передаются в JSON — в том же виде, в каком их отдаёт REST API. Плагины
+// пишутся на Go через пакет plugin, на других языках — по этому файлу.
+
+package pluginv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ProcessorService_Describe_FullMethodName         = "/tflogs.plugin.v1.ProcessorService/Describe"
+	ProcessorService_OnEntry_FullMethodName          = "/tflogs.plugin.v1.ProcessorService/OnEntry"
+	ProcessorService_OnUploadComplete_FullMethodName = "/tflogs.plugin.v1.ProcessorService/OnUploadComplete"
+	ProcessorService_Export_FullMethodName           = "/tflogs.plugin.v1.ProcessorService/Export"
+)
+
+// ProcessorServiceClient is the client API for ProcessorService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ProcessorServiceClient interface {
+	// Describe сообщает, какие хуки и форматы экспорта реализует плагин.
+	Describe(ctx context.Context, in *DescribeRequest, opts ...grpc.CallOption) (*DescribeResponse, error)
+	// OnEntry вызывается при загрузке для пачки записей и возвращает их в том
+	// же количестве и порядке.
+	OnEntry(ctx context.Context, in *OnEntryRequest, opts ...grpc.CallOption) (*OnEntryResponse, error)
+	// OnUploadComplete — уведомление о завершённой загрузке.
+	OnUploadComplete(ctx context.Context, in *OnUploadCompleteRequest, opts ...grpc.CallOption) (*OnUploadCompleteResponse, error)
+	// Export выгружает записи в одном из объявленных плагином форматов.
+	Export(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (*ExportResponse, error)
+}
+
+type processorServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewProcessorServiceClient(cc grpc.ClientConnInterface) ProcessorServiceClient {
+	return &processorServiceClient{cc}
+}
+
+func (c *processorServiceClient) Describe(ctx context.Context, in *DescribeRequest, opts ...grpc.CallOption) (*DescribeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DescribeResponse)
+	err := c.cc.Invoke(ctx, ProcessorService_Describe_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *processorServiceClient) OnEntry(ctx context.Context, in *OnEntryRequest, opts ...grpc.CallOption) (*OnEntryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OnEntryResponse)
+	err := c.cc.Invoke(ctx, ProcessorService_OnEntry_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *processorServiceClient) OnUploadComplete(ctx context.Context, in *OnUploadCompleteRequest, opts ...grpc.CallOption) (*OnUploadCompleteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OnUploadCompleteResponse)
+	err := c.cc.Invoke(ctx, ProcessorService_OnUploadComplete_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *processorServiceClient) Export(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (*ExportResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportResponse)
+	err := c.cc.Invoke(ctx, ProcessorService_Export_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ProcessorServiceServer is the server API for ProcessorService service.
+// All implementations must embed UnimplementedProcessorServiceServer
+// for forward compatibility.
+type ProcessorServiceServer interface {
+	// Describe сообщает, какие хуки и форматы экспорта реализует плагин.
+	Describe(context.Context, *DescribeRequest) (*DescribeResponse, error)
+	// OnEntry вызывается при загрузке для пачки записей и возвращает их в том
+	// же количестве и порядке.
+	OnEntry(context.Context, *OnEntryRequest) (*OnEntryResponse, error)
+	// OnUploadComplete — уведомление о завершённой загрузке.
+	OnUploadComplete(context.Context, *OnUploadCompleteRequest) (*OnUploadCompleteResponse, error)
+	// Export выгружает записи в одном из объявленных плагином форматов.
+	Export(context.Context, *ExportRequest) (*ExportResponse, error)
+	mustEmbedUnimplementedProcessorServiceServer()
+}
+
+// UnimplementedProcessorServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedProcessorServiceServer struct{}
+
+func (UnimplementedProcessorServiceServer) Describe(context.Context, *DescribeRequest) (*DescribeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Describe not implemented")
+}
+func (UnimplementedProcessorServiceServer) OnEntry(context.Context, *OnEntryRequest) (*OnEntryResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method OnEntry not implemented")
+}
+func (UnimplementedProcessorServiceServer) OnUploadComplete(context.Context, *OnUploadCompleteRequest) (*OnUploadCompleteResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method OnUploadComplete not implemented")
+}
+func (UnimplementedProcessorServiceServer) Export(context.Context, *ExportRequest) (*ExportResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Export not implemented")
+}
+func (UnimplementedProcessorServiceServer) mustEmbedUnimplementedProcessorServiceServer() {}
+func (UnimplementedProcessorServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeProcessorServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ProcessorServiceServer will
+// result in compilation errors.
+type UnsafeProcessorServiceServer interface {
+	mustEmbedUnimplementedProcessorServiceServer()
+}
+
+func RegisterProcessorServiceServer(s grpc.ServiceRegistrar, srv ProcessorServiceServer) {
+	// If the following call panics, it indicates UnimplementedProcessorServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ProcessorService_ServiceDesc, srv)
+}
+
+func _ProcessorService_Describe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DescribeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProcessorServiceServer).Describe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProcessorService_Describe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProcessorServiceServer).Describe(ctx, req.(*DescribeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProcessorService_OnEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OnEntryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProcessorServiceServer).OnEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProcessorService_OnEntry_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProcessorServiceServer).OnEntry(ctx, req.(*OnEntryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProcessorService_OnUploadComplete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OnUploadCompleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProcessorServiceServer).OnUploadComplete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProcessorService_OnUploadComplete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProcessorServiceServer).OnUploadComplete(ctx, req.(*OnUploadCompleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProcessorService_Export_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProcessorServiceServer).Export(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProcessorService_Export_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProcessorServiceServer).Export(ctx, req.(*ExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ProcessorService_ServiceDesc is the grpc.ServiceDesc for ProcessorService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ProcessorService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "tflogs.plugin.v1.ProcessorService",
+	HandlerType: (*ProcessorServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Describe",
+			Handler:    _ProcessorService_Describe_Handler,
+		},
+		{
+			MethodName: "OnEntry",
+			Handler:    _ProcessorService_OnEntry_Handler,
+		},
+		{
+			MethodName: "OnUploadComplete",
+			Handler:    _ProcessorService_OnUploadComplete_Handler,
+		},
+		{
+			MethodName: "Export",
+			Handler:    _ProcessorService_Export_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "tflogs/plugin/v1/plugin.proto",
+}
@@ -0,0 +1,1255 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: tflogs/v1/tflogs.proto
+
+// gRPC API поверх того же хранилища, что и REST. Рабочее пространство
+// выбирается метаданными x-workspace, токен — authorization: Bearer <token>.
+
+package tflogsv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type IngestRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	FileName    string                 `protobuf:"bytes,1,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	TfWorkspace string                 `protobuf:"bytes,2,opt,name=tf_workspace,json=tfWorkspace,proto3" json:"tf_workspace,omitempty"`
+	RunId       string                 `protobuf:"bytes,3,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	// Строки NDJSON без завершающего перевода строки.
+	Lines         []string `protobuf:"bytes,4,rep,name=lines,proto3" json:"lines,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IngestRequest) Reset() {
+	*x = IngestRequest{}
+	mi := &file_tflogs_v1_tflogs_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IngestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IngestRequest) ProtoMessage() {}
+
+func (x *IngestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tflogs_v1_tflogs_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IngestRequest.ProtoReflect.Descriptor instead.
+func (*IngestRequest) Descriptor() ([]byte, []int) {
+	return file_tflogs_v1_tflogs_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *IngestRequest) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *IngestRequest) GetTfWorkspace() string {
+	if x != nil {
+		return x.TfWorkspace
+	}
+	return ""
+}
+
+func (x *IngestRequest) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *IngestRequest) GetLines() []string {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+type ErrorFingerprint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Fingerprint   string                 `protobuf:"bytes,1,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"`
+	Severity      string                 `protobuf:"bytes,2,opt,name=severity,proto3" json:"severity,omitempty"`
+	ResourceType  string                 `protobuf:"bytes,3,opt,name=resource_type,json=resourceType,proto3" json:"resource_type,omitempty"`
+	TfRpc         string                 `protobuf:"bytes,4,opt,name=tf_rpc,json=tfRpc,proto3" json:"tf_rpc,omitempty"`
+	TemplateId    string                 `protobuf:"bytes,5,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
+	Template      string                 `protobuf:"bytes,6,opt,name=template,proto3" json:"template,omitempty"`
+	ErrorCode     string                 `protobuf:"bytes,7,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	Summary       string                 `protobuf:"bytes,8,opt,name=summary,proto3" json:"summary,omitempty"`
+	Count         int32                  `protobuf:"varint,9,opt,name=count,proto3" json:"count,omitempty"`
+	SampleLogId   string                 `protobuf:"bytes,10,opt,name=sample_log_id,json=sampleLogId,proto3" json:"sample_log_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ErrorFingerprint) Reset() {
+	*x = ErrorFingerprint{}
+	mi := &file_tflogs_v1_tflogs_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ErrorFingerprint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ErrorFingerprint) ProtoMessage() {}
+
+func (x *ErrorFingerprint) ProtoReflect() protoreflect.Message {
+	mi := &file_tflogs_v1_tflogs_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ErrorFingerprint.ProtoReflect.Descriptor instead.
+func (*ErrorFingerprint) Descriptor() ([]byte, []int) {
+	return file_tflogs_v1_tflogs_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ErrorFingerprint) GetFingerprint() string {
+	if x != nil {
+		return x.Fingerprint
+	}
+	return ""
+}
+
+func (x *ErrorFingerprint) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *ErrorFingerprint) GetResourceType() string {
+	if x != nil {
+		return x.ResourceType
+	}
+	return ""
+}
+
+func (x *ErrorFingerprint) GetTfRpc() string {
+	if x != nil {
+		return x.TfRpc
+	}
+	return ""
+}
+
+func (x *ErrorFingerprint) GetTemplateId() string {
+	if x != nil {
+		return x.TemplateId
+	}
+	return ""
+}
+
+func (x *ErrorFingerprint) GetTemplate() string {
+	if x != nil {
+		return x.Template
+	}
+	return ""
+}
+
+func (x *ErrorFingerprint) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+func (x *ErrorFingerprint) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *ErrorFingerprint) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *ErrorFingerprint) GetSampleLogId() string {
+	if x != nil {
+		return x.SampleLogId
+	}
+	return ""
+}
+
+type IngestResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// parsed или duplicate
+	Status        string              `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	DuplicateOf   string              `protobuf:"bytes,3,opt,name=duplicate_of,json=duplicateOf,proto3" json:"duplicate_of,omitempty"`
+	TfWorkspace   string              `protobuf:"bytes,4,opt,name=tf_workspace,json=tfWorkspace,proto3" json:"tf_workspace,omitempty"`
+	Lines         int32               `protobuf:"varint,5,opt,name=lines,proto3" json:"lines,omitempty"`
+	Corrupted     int32               `protobuf:"varint,6,opt,name=corrupted,proto3" json:"corrupted,omitempty"`
+	Duplicates    int32               `protobuf:"varint,7,opt,name=duplicates,proto3" json:"duplicates,omitempty"`
+	Novel         []*ErrorFingerprint `protobuf:"bytes,8,rep,name=novel,proto3" json:"novel,omitempty"`
+	Disappeared   []*ErrorFingerprint `protobuf:"bytes,9,rep,name=disappeared,proto3" json:"disappeared,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IngestResponse) Reset() {
+	*x = IngestResponse{}
+	mi := &file_tflogs_v1_tflogs_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IngestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IngestResponse) ProtoMessage() {}
+
+func (x *IngestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tflogs_v1_tflogs_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IngestResponse.ProtoReflect.Descriptor instead.
+func (*IngestResponse) Descriptor() ([]byte, []int) {
+	return file_tflogs_v1_tflogs_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *IngestResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *IngestResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *IngestResponse) GetDuplicateOf() string {
+	if x != nil {
+		return x.DuplicateOf
+	}
+	return ""
+}
+
+func (x *IngestResponse) GetTfWorkspace() string {
+	if x != nil {
+		return x.TfWorkspace
+	}
+	return ""
+}
+
+func (x *IngestResponse) GetLines() int32 {
+	if x != nil {
+		return x.Lines
+	}
+	return 0
+}
+
+func (x *IngestResponse) GetCorrupted() int32 {
+	if x != nil {
+		return x.Corrupted
+	}
+	return 0
+}
+
+func (x *IngestResponse) GetDuplicates() int32 {
+	if x != nil {
+		return x.Duplicates
+	}
+	return 0
+}
+
+func (x *IngestResponse) GetNovel() []*ErrorFingerprint {
+	if x != nil {
+		return x.Novel
+	}
+	return nil
+}
+
+func (x *IngestResponse) GetDisappeared() []*ErrorFingerprint {
+	if x != nil {
+		return x.Disappeared
+	}
+	return nil
+}
+
+// Фильтры — как у GET /logs; query — язык запросов (параметр q).
+type QueryRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	TfResourceType string                 `protobuf:"bytes,1,opt,name=tf_resource_type,json=tfResourceType,proto3" json:"tf_resource_type,omitempty"`
+	TimestampFrom  string                 `protobuf:"bytes,2,opt,name=timestamp_from,json=timestampFrom,proto3" json:"timestamp_from,omitempty"`
+	TimestampTo    string                 `protobuf:"bytes,3,opt,name=timestamp_to,json=timestampTo,proto3" json:"timestamp_to,omitempty"`
+	Level          string                 `protobuf:"bytes,4,opt,name=level,proto3" json:"level,omitempty"`
+	Search         string                 `protobuf:"bytes,5,opt,name=search,proto3" json:"search,omitempty"`
+	Query          string                 `protobuf:"bytes,6,opt,name=query,proto3" json:"query,omitempty"`
+	TemplateId     string                 `protobuf:"bytes,7,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
+	TfReqId        string                 `protobuf:"bytes,8,opt,name=tf_req_id,json=tfReqId,proto3" json:"tf_req_id,omitempty"`
+	State          string                 `protobuf:"bytes,9,opt,name=state,proto3" json:"state,omitempty"`
+	Assignee       string                 `protobuf:"bytes,10,opt,name=assignee,proto3" json:"assignee,omitempty"`
+	// 0 — без ограничения.
+	Limit         int32 `protobuf:"varint,11,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryRequest) Reset() {
+	*x = QueryRequest{}
+	mi := &file_tflogs_v1_tflogs_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryRequest) ProtoMessage() {}
+
+func (x *QueryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tflogs_v1_tflogs_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryRequest.ProtoReflect.Descriptor instead.
+func (*QueryRequest) Descriptor() ([]byte, []int) {
+	return file_tflogs_v1_tflogs_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *QueryRequest) GetTfResourceType() string {
+	if x != nil {
+		return x.TfResourceType
+	}
+	return ""
+}
+
+func (x *QueryRequest) GetTimestampFrom() string {
+	if x != nil {
+		return x.TimestampFrom
+	}
+	return ""
+}
+
+func (x *QueryRequest) GetTimestampTo() string {
+	if x != nil {
+		return x.TimestampTo
+	}
+	return ""
+}
+
+func (x *QueryRequest) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *QueryRequest) GetSearch() string {
+	if x != nil {
+		return x.Search
+	}
+	return ""
+}
+
+func (x *QueryRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *QueryRequest) GetTemplateId() string {
+	if x != nil {
+		return x.TemplateId
+	}
+	return ""
+}
+
+func (x *QueryRequest) GetTfReqId() string {
+	if x != nil {
+		return x.TfReqId
+	}
+	return ""
+}
+
+func (x *QueryRequest) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *QueryRequest) GetAssignee() string {
+	if x != nil {
+		return x.Assignee
+	}
+	return ""
+}
+
+func (x *QueryRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type QueryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entry         *LogEntry              `protobuf:"bytes,1,opt,name=entry,proto3" json:"entry,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryResponse) Reset() {
+	*x = QueryResponse{}
+	mi := &file_tflogs_v1_tflogs_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryResponse) ProtoMessage() {}
+
+func (x *QueryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tflogs_v1_tflogs_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryResponse.ProtoReflect.Descriptor instead.
+func (*QueryResponse) Descriptor() ([]byte, []int) {
+	return file_tflogs_v1_tflogs_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *QueryResponse) GetEntry() *LogEntry {
+	if x != nil {
+		return x.Entry
+	}
+	return nil
+}
+
+type TailRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Язык запросов; пусто — все записи.
+	Query         string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TailRequest) Reset() {
+	*x = TailRequest{}
+	mi := &file_tflogs_v1_tflogs_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TailRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TailRequest) ProtoMessage() {}
+
+func (x *TailRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tflogs_v1_tflogs_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TailRequest.ProtoReflect.Descriptor instead.
+func (*TailRequest) Descriptor() ([]byte, []int) {
+	return file_tflogs_v1_tflogs_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TailRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+type TailResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entry         *LogEntry              `protobuf:"bytes,1,opt,name=entry,proto3" json:"entry,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TailResponse) Reset() {
+	*x = TailResponse{}
+	mi := &file_tflogs_v1_tflogs_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TailResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TailResponse) ProtoMessage() {}
+
+func (x *TailResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tflogs_v1_tflogs_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TailResponse.ProtoReflect.Descriptor instead.
+func (*TailResponse) Descriptor() ([]byte, []int) {
+	return file_tflogs_v1_tflogs_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *TailResponse) GetEntry() *LogEntry {
+	if x != nil {
+		return x.Entry
+	}
+	return nil
+}
+
+type LogEntry struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Timestamp      string                 `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Level          string                 `protobuf:"bytes,3,opt,name=level,proto3" json:"level,omitempty"`
+	Message        string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	Module         string                 `protobuf:"bytes,5,opt,name=module,proto3" json:"module,omitempty"`
+	Severity       string                 `protobuf:"bytes,6,opt,name=severity,proto3" json:"severity,omitempty"`
+	TfReqId        string                 `protobuf:"bytes,7,opt,name=tf_req_id,json=tfReqId,proto3" json:"tf_req_id,omitempty"`
+	TfRpc          string                 `protobuf:"bytes,8,opt,name=tf_rpc,json=tfRpc,proto3" json:"tf_rpc,omitempty"`
+	TfResourceType string                 `protobuf:"bytes,9,opt,name=tf_resource_type,json=tfResourceType,proto3" json:"tf_resource_type,omitempty"`
+	HttpMethod     string                 `protobuf:"bytes,10,opt,name=http_method,json=httpMethod,proto3" json:"http_method,omitempty"`
+	HttpUri        string                 `protobuf:"bytes,11,opt,name=http_uri,json=httpUri,proto3" json:"http_uri,omitempty"`
+	HttpStatus     int32                  `protobuf:"varint,12,opt,name=http_status,json=httpStatus,proto3" json:"http_status,omitempty"`
+	UploadId       string                 `protobuf:"bytes,13,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	TemplateId     string                 `protobuf:"bytes,14,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
+	// Запись целиком в том виде, в каком её отдаёт REST API.
+	Json          []byte `protobuf:"bytes,15,opt,name=json,proto3" json:"json,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogEntry) Reset() {
+	*x = LogEntry{}
+	mi := &file_tflogs_v1_tflogs_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogEntry) ProtoMessage() {}
+
+func (x *LogEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_tflogs_v1_tflogs_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogEntry.ProtoReflect.Descriptor instead.
+func (*LogEntry) Descriptor() ([]byte, []int) {
+	return file_tflogs_v1_tflogs_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *LogEntry) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *LogEntry) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+func (x *LogEntry) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *LogEntry) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *LogEntry) GetModule() string {
+	if x != nil {
+		return x.Module
+	}
+	return ""
+}
+
+func (x *LogEntry) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *LogEntry) GetTfReqId() string {
+	if x != nil {
+		return x.TfReqId
+	}
+	return ""
+}
+
+func (x *LogEntry) GetTfRpc() string {
+	if x != nil {
+		return x.TfRpc
+	}
+	return ""
+}
+
+func (x *LogEntry) GetTfResourceType() string {
+	if x != nil {
+		return x.TfResourceType
+	}
+	return ""
+}
+
+func (x *LogEntry) GetHttpMethod() string {
+	if x != nil {
+		return x.HttpMethod
+	}
+	return ""
+}
+
+func (x *LogEntry) GetHttpUri() string {
+	if x != nil {
+		return x.HttpUri
+	}
+	return ""
+}
+
+func (x *LogEntry) GetHttpStatus() int32 {
+	if x != nil {
+		return x.HttpStatus
+	}
+	return 0
+}
+
+func (x *LogEntry) GetUploadId() string {
+	if x != nil {
+		return x.UploadId
+	}
+	return ""
+}
+
+func (x *LogEntry) GetTemplateId() string {
+	if x != nil {
+		return x.TemplateId
+	}
+	return ""
+}
+
+func (x *LogEntry) GetJson() []byte {
+	if x != nil {
+		return x.Json
+	}
+	return nil
+}
+
+type GetGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TfReqId       string                 `protobuf:"bytes,1,opt,name=tf_req_id,json=tfReqId,proto3" json:"tf_req_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetGroupRequest) Reset() {
+	*x = GetGroupRequest{}
+	mi := &file_tflogs_v1_tflogs_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetGroupRequest) ProtoMessage() {}
+
+func (x *GetGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tflogs_v1_tflogs_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetGroupRequest.ProtoReflect.Descriptor instead.
+func (*GetGroupRequest) Descriptor() ([]byte, []int) {
+	return file_tflogs_v1_tflogs_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetGroupRequest) GetTfReqId() string {
+	if x != nil {
+		return x.TfReqId
+	}
+	return ""
+}
+
+type GetGroupResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*LogEntry            `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetGroupResponse) Reset() {
+	*x = GetGroupResponse{}
+	mi := &file_tflogs_v1_tflogs_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetGroupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetGroupResponse) ProtoMessage() {}
+
+func (x *GetGroupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tflogs_v1_tflogs_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetGroupResponse.ProtoReflect.Descriptor instead.
+func (*GetGroupResponse) Descriptor() ([]byte, []int) {
+	return file_tflogs_v1_tflogs_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetGroupResponse) GetEntries() []*LogEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type GetTimelineRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTimelineRequest) Reset() {
+	*x = GetTimelineRequest{}
+	mi := &file_tflogs_v1_tflogs_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTimelineRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTimelineRequest) ProtoMessage() {}
+
+func (x *GetTimelineRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tflogs_v1_tflogs_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTimelineRequest.ProtoReflect.Descriptor instead.
+func (*GetTimelineRequest) Descriptor() ([]byte, []int) {
+	return file_tflogs_v1_tflogs_proto_rawDescGZIP(), []int{10}
+}
+
+type TimelineEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TfReqId       string                 `protobuf:"bytes,1,opt,name=tf_req_id,json=tfReqId,proto3" json:"tf_req_id,omitempty"`
+	Start         string                 `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
+	End           string                 `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimelineEntry) Reset() {
+	*x = TimelineEntry{}
+	mi := &file_tflogs_v1_tflogs_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimelineEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimelineEntry) ProtoMessage() {}
+
+func (x *TimelineEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_tflogs_v1_tflogs_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimelineEntry.ProtoReflect.Descriptor instead.
+func (*TimelineEntry) Descriptor() ([]byte, []int) {
+	return file_tflogs_v1_tflogs_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *TimelineEntry) GetTfReqId() string {
+	if x != nil {
+		return x.TfReqId
+	}
+	return ""
+}
+
+func (x *TimelineEntry) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *TimelineEntry) GetEnd() string {
+	if x != nil {
+		return x.End
+	}
+	return ""
+}
+
+func (x *TimelineEntry) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type GetTimelineResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*TimelineEntry       `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTimelineResponse) Reset() {
+	*x = GetTimelineResponse{}
+	mi := &file_tflogs_v1_tflogs_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTimelineResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTimelineResponse) ProtoMessage() {}
+
+func (x *GetTimelineResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tflogs_v1_tflogs_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTimelineResponse.ProtoReflect.Descriptor instead.
+func (*GetTimelineResponse) Descriptor() ([]byte, []int) {
+	return file_tflogs_v1_tflogs_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetTimelineResponse) GetEntries() []*TimelineEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type GetMetricsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMetricsRequest) Reset() {
+	*x = GetMetricsRequest{}
+	mi := &file_tflogs_v1_tflogs_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMetricsRequest) ProtoMessage() {}
+
+func (x *GetMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tflogs_v1_tflogs_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMetricsRequest.ProtoReflect.Descriptor instead.
+func (*GetMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_tflogs_v1_tflogs_proto_rawDescGZIP(), []int{13}
+}
+
+type GetMetricsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Errors        int32                  `protobuf:"varint,1,opt,name=errors,proto3" json:"errors,omitempty"`
+	Warnings      int32                  `protobuf:"varint,2,opt,name=warnings,proto3" json:"warnings,omitempty"`
+	Levels        map[string]int32       `protobuf:"bytes,3,rep,name=levels,proto3" json:"levels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMetricsResponse) Reset() {
+	*x = GetMetricsResponse{}
+	mi := &file_tflogs_v1_tflogs_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMetricsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMetricsResponse) ProtoMessage() {}
+
+func (x *GetMetricsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tflogs_v1_tflogs_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMetricsResponse.ProtoReflect.Descriptor instead.
+func (*GetMetricsResponse) Descriptor() ([]byte, []int) {
+	return file_tflogs_v1_tflogs_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetMetricsResponse) GetErrors() int32 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+func (x *GetMetricsResponse) GetWarnings() int32 {
+	if x != nil {
+		return x.Warnings
+	}
+	return 0
+}
+
+func (x *GetMetricsResponse) GetLevels() map[string]int32 {
+	if x != nil {
+		return x.Levels
+	}
+	return nil
+}
+
+var File_tflogs_v1_tflogs_proto protoreflect.FileDescriptor
+
+const file_tflogs_v1_tflogs_proto_rawDesc = "" +
+	"\n" +
+	"\x16tflogs/v1/tflogs.proto\x12\ttflogs.v1\"|\n" +
+	"\rIngestRequest\x12\x1b\n" +
+	"\tfile_name\x18\x01 \x01(\tR\bfileName\x12!\n" +
+	"\ftf_workspace\x18\x02 \x01(\tR\vtfWorkspace\x12\x15\n" +
+	"\x06run_id\x18\x03 \x01(\tR\x05runId\x12\x14\n" +
+	"\x05lines\x18\x04 \x03(\tR\x05lines\"\xbc\x02\n" +
+	"\x10ErrorFingerprint\x12 \n" +
+	"\vfingerprint\x18\x01 \x01(\tR\vfingerprint\x12\x1a\n" +
+	"\bseverity\x18\x02 \x01(\tR\bseverity\x12#\n" +
+	"\rresource_type\x18\x03 \x01(\tR\fresourceType\x12\x15\n" +
+	"\x06tf_rpc\x18\x04 \x01(\tR\x05tfRpc\x12\x1f\n" +
+	"\vtemplate_id\x18\x05 \x01(\tR\n" +
+	"templateId\x12\x1a\n" +
+	"\btemplate\x18\x06 \x01(\tR\btemplate\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\a \x01(\tR\terrorCode\x12\x18\n" +
+	"\asummary\x18\b \x01(\tR\asummary\x12\x14\n" +
+	"\x05count\x18\t \x01(\x05R\x05count\x12\"\n" +
+	"\rsample_log_id\x18\n" +
+	" \x01(\tR\vsampleLogId\"\xc4\x02\n" +
+	"\x0eIngestResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12!\n" +
+	"\fduplicate_of\x18\x03 \x01(\tR\vduplicateOf\x12!\n" +
+	"\ftf_workspace\x18\x04 \x01(\tR\vtfWorkspace\x12\x14\n" +
+	"\x05lines\x18\x05 \x01(\x05R\x05lines\x12\x1c\n" +
+	"\tcorrupted\x18\x06 \x01(\x05R\tcorrupted\x12\x1e\n" +
+	"\n" +
+	"duplicates\x18\a \x01(\x05R\n" +
+	"duplicates\x121\n" +
+	"\x05novel\x18\b \x03(\v2\x1b.tflogs.v1.ErrorFingerprintR\x05novel\x12=\n" +
+	"\vdisappeared\x18\t \x03(\v2\x1b.tflogs.v1.ErrorFingerprintR\vdisappeared\"\xcb\x02\n" +
+	"\fQueryRequest\x12(\n" +
+	"\x10tf_resource_type\x18\x01 \x01(\tR\x0etfResourceType\x12%\n" +
+	"\x0etimestamp_from\x18\x02 \x01(\tR\rtimestampFrom\x12!\n" +
+	"\ftimestamp_to\x18\x03 \x01(\tR\vtimestampTo\x12\x14\n" +
+	"\x05level\x18\x04 \x01(\tR\x05level\x12\x16\n" +
+	"\x06search\x18\x05 \x01(\tR\x06search\x12\x14\n" +
+	"\x05query\x18\x06 \x01(\tR\x05query\x12\x1f\n" +
+	"\vtemplate_id\x18\a \x01(\tR\n" +
+	"templateId\x12\x1a\n" +
+	"\ttf_req_id\x18\b \x01(\tR\atfReqId\x12\x14\n" +
+	"\x05state\x18\t \x01(\tR\x05state\x12\x1a\n" +
+	"\bassignee\x18\n" +
+	" \x01(\tR\bassignee\x12\x14\n" +
+	"\x05limit\x18\v \x01(\x05R\x05limit\":\n" +
+	"\rQueryResponse\x12)\n" +
+	"\x05entry\x18\x01 \x01(\v2\x13.tflogs.v1.LogEntryR\x05entry\"#\n" +
+	"\vTailRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\"9\n" +
+	"\fTailResponse\x12)\n" +
+	"\x05entry\x18\x01 \x01(\v2\x13.tflogs.v1.LogEntryR\x05entry\"\xa8\x03\n" +
+	"\bLogEntry\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1c\n" +
+	"\ttimestamp\x18\x02 \x01(\tR\ttimestamp\x12\x14\n" +
+	"\x05level\x18\x03 \x01(\tR\x05level\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\x12\x16\n" +
+	"\x06module\x18\x05 \x01(\tR\x06module\x12\x1a\n" +
+	"\bseverity\x18\x06 \x01(\tR\bseverity\x12\x1a\n" +
+	"\ttf_req_id\x18\a \x01(\tR\atfReqId\x12\x15\n" +
+	"\x06tf_rpc\x18\b \x01(\tR\x05tfRpc\x12(\n" +
+	"\x10tf_resource_type\x18\t \x01(\tR\x0etfResourceType\x12\x1f\n" +
+	"\vhttp_method\x18\n" +
+	" \x01(\tR\n" +
+	"httpMethod\x12\x19\n" +
+	"\bhttp_uri\x18\v \x01(\tR\ahttpUri\x12\x1f\n" +
+	"\vhttp_status\x18\f \x01(\x05R\n" +
+	"httpStatus\x12\x1b\n" +
+	"\tupload_id\x18\r \x01(\tR\buploadId\x12\x1f\n" +
+	"\vtemplate_id\x18\x0e \x01(\tR\n" +
+	"templateId\x12\x12\n" +
+	"\x04json\x18\x0f \x01(\fR\x04json\"-\n" +
+	"\x0fGetGroupRequest\x12\x1a\n" +
+	"\ttf_req_id\x18\x01 \x01(\tR\atfReqId\"A\n" +
+	"\x10GetGroupResponse\x12-\n" +
+	"\aentries\x18\x01 \x03(\v2\x13.tflogs.v1.LogEntryR\aentries\"\x14\n" +
+	"\x12GetTimelineRequest\"k\n" +
+	"\rTimelineEntry\x12\x1a\n" +
+	"\ttf_req_id\x18\x01 \x01(\tR\atfReqId\x12\x14\n" +
+	"\x05start\x18\x02 \x01(\tR\x05start\x12\x10\n" +
+	"\x03end\x18\x03 \x01(\tR\x03end\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\"I\n" +
+	"\x13GetTimelineResponse\x122\n" +
+	"\aentries\x18\x01 \x03(\v2\x18.tflogs.v1.TimelineEntryR\aentries\"\x13\n" +
+	"\x11GetMetricsRequest\"\xc6\x01\n" +
+	"\x12GetMetricsResponse\x12\x16\n" +
+	"\x06errors\x18\x01 \x01(\x05R\x06errors\x12\x1a\n" +
+	"\bwarnings\x18\x02 \x01(\x05R\bwarnings\x12A\n" +
+	"\x06levels\x18\x03 \x03(\v2).tflogs.v1.GetMetricsResponse.LevelsEntryR\x06levels\x1a9\n" +
+	"\vLevelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x012\xa4\x03\n" +
+	"\n" +
+	"LogService\x12?\n" +
+	"\x06Ingest\x12\x18.tflogs.v1.IngestRequest\x1a\x19.tflogs.v1.IngestResponse(\x01\x12<\n" +
+	"\x05Query\x12\x17.tflogs.v1.QueryRequest\x1a\x18.tflogs.v1.QueryResponse0\x01\x129\n" +
+	"\x04Tail\x12\x16.tflogs.v1.TailRequest\x1a\x17.tflogs.v1.TailResponse0\x01\x12C\n" +
+	"\bGetGroup\x12\x1a.tflogs.v1.GetGroupRequest\x1a\x1b.tflogs.v1.GetGroupResponse\x12L\n" +
+	"\vGetTimeline\x12\x1d.tflogs.v1.GetTimelineRequest\x1a\x1e.tflogs.v1.GetTimelineResponse\x12I\n" +
+	"\n" +
+	"GetMetrics\x12\x1c.tflogs.v1.GetMetricsRequest\x1a\x1d.tflogs.v1.GetMetricsResponseBDZBgitlab.com/paradaise1/t1-hackaton-terraform/gen/tflogs/v1;tflogsv1b\x06proto3"
+
+var (
+	file_tflogs_v1_tflogs_proto_rawDescOnce sync.Once
+	file_tflogs_v1_tflogs_proto_rawDescData []byte
+)
+
+func file_tflogs_v1_tflogs_proto_rawDescGZIP() []byte {
+	file_tflogs_v1_tflogs_proto_rawDescOnce.Do(func() {
+		file_tflogs_v1_tflogs_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_tflogs_v1_tflogs_proto_rawDesc), len(file_tflogs_v1_tflogs_proto_rawDesc)))
+	})
+	return file_tflogs_v1_tflogs_proto_rawDescData
+}
+
+var file_tflogs_v1_tflogs_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_tflogs_v1_tflogs_proto_goTypes = []any{
+	(*IngestRequest)(nil),       // 0: tflogs.v1.IngestRequest
+	(*ErrorFingerprint)(nil),    // 1: tflogs.v1.ErrorFingerprint
+	(*IngestResponse)(nil),      // 2: tflogs.v1.IngestResponse
+	(*QueryRequest)(nil),        // 3: tflogs.v1.QueryRequest
+	(*QueryResponse)(nil),       // 4: tflogs.v1.QueryResponse
+	(*TailRequest)(nil),         // 5: tflogs.v1.TailRequest
+	(*TailResponse)(nil),        // 6: tflogs.v1.TailResponse
+	(*LogEntry)(nil),            // 7: tflogs.v1.LogEntry
+	(*GetGroupRequest)(nil),     // 8: tflogs.v1.GetGroupRequest
+	(*GetGroupResponse)(nil),    // 9: tflogs.v1.GetGroupResponse
+	(*GetTimelineRequest)(nil),  // 10: tflogs.v1.GetTimelineRequest
+	(*TimelineEntry)(nil),       // 11: tflogs.v1.TimelineEntry
+	(*GetTimelineResponse)(nil), // 12: tflogs.v1.GetTimelineResponse
+	(*GetMetricsRequest)(nil),   // 13: tflogs.v1.GetMetricsRequest
+	(*GetMetricsResponse)(nil),  // 14: tflogs.v1.GetMetricsResponse
+	nil,                         // 15: tflogs.v1.GetMetricsResponse.LevelsEntry
+}
+var file_tflogs_v1_tflogs_proto_depIdxs = []int32{
+	1,  // 0: tflogs.v1.IngestResponse.novel:type_name -> tflogs.v1.ErrorFingerprint
+	1,  // 1: tflogs.v1.IngestResponse.disappeared:type_name -> tflogs.v1.ErrorFingerprint
+	7,  // 2: tflogs.v1.QueryResponse.entry:type_name -> tflogs.v1.LogEntry
+	7,  // 3: tflogs.v1.TailResponse.entry:type_name -> tflogs.v1.LogEntry
+	7,  // 4: tflogs.v1.GetGroupResponse.entries:type_name -> tflogs.v1.LogEntry
+	11, // 5: tflogs.v1.GetTimelineResponse.entries:type_name -> tflogs.v1.TimelineEntry
+	15, // 6: tflogs.v1.GetMetricsResponse.levels:type_name -> tflogs.v1.GetMetricsResponse.LevelsEntry
+	0,  // 7: tflogs.v1.LogService.Ingest:input_type -> tflogs.v1.IngestRequest
+	3,  // 8: tflogs.v1.LogService.Query:input_type -> tflogs.v1.QueryRequest
+	5,  // 9: tflogs.v1.LogService.Tail:input_type -> tflogs.v1.TailRequest
+	8,  // 10: tflogs.v1.LogService.GetGroup:input_type -> tflogs.v1.GetGroupRequest
+	10, // 11: tflogs.v1.LogService.GetTimeline:input_type -> tflogs.v1.GetTimelineRequest
+	13, // 12: tflogs.v1.LogService.GetMetrics:input_type -> tflogs.v1.GetMetricsRequest
+	2,  // 13: tflogs.v1.LogService.Ingest:output_type -> tflogs.v1.IngestResponse
+	4,  // 14: tflogs.v1.LogService.Query:output_type -> tflogs.v1.QueryResponse
+	6,  // 15: tflogs.v1.LogService.Tail:output_type -> tflogs.v1.TailResponse
+	9,  // 16: tflogs.v1.LogService.GetGroup:output_type -> tflogs.v1.GetGroupResponse
+	12, // 17: tflogs.v1.LogService.GetTimeline:output_type -> tflogs.v1.GetTimelineResponse
+	14, // 18: tflogs.v1.LogService.GetMetrics:output_type -> tflogs.v1.GetMetricsResponse
+	13, // [13:19] is the sub-list for method output_type
+	7,  // [7:13] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_tflogs_v1_tflogs_proto_init() }
+func file_tflogs_v1_tflogs_proto_init() {
+	if File_tflogs_v1_tflogs_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_tflogs_v1_tflogs_proto_rawDesc), len(file_tflogs_v1_tflogs_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   16,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_tflogs_v1_tflogs_proto_goTypes,
+		DependencyIndexes: file_tflogs_v1_tflogs_proto_depIdxs,
+		MessageInfos:      file_tflogs_v1_tflogs_proto_msgTypes,
+	}.Build()
+	File_tflogs_v1_tflogs_proto = out.File
+	file_tflogs_v1_tflogs_proto_goTypes = nil
+	file_tflogs_v1_tflogs_proto_depIdxs = nil
+}
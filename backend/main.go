@@ -1,11 +1,15 @@
 package main
 
 import (
+	"fmt"
+	"os"
+
 	"gitlab.com/paradaise1/t1-hackaton-terraform/app"
 )
 
 func main() {
-	if err := app.Run(); err != nil {
-		panic(err)
+	if err := app.Run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,185 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+
+	goplugin "github.com/hashicorp/go-plugin"
+	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+	pluginv1 "gitlab.com/paradaise1/t1-hackaton-terraform/gen/tflogs/plugin/v1"
+	"google.golang.org/grpc"
+)
+
+type grpcPlugin struct {
+	goplugin.NetRPCUnsupportedPlugin
+	impl Processor // только на стороне плагина
+}
+
+func (p *grpcPlugin) GRPCServer(_ *goplugin.GRPCBroker, s *grpc.Server) error {
+	pluginv1.RegisterProcessorServiceServer(s, &server{impl: p.impl})
+	return nil
+}
+
+func (p *grpcPlugin) GRPCClient(_ context.Context, _ *goplugin.GRPCBroker, c *grpc.ClientConn) (any, error) {
+	return &remote{client: pluginv1.NewProcessorServiceClient(c)}, nil
+}
+
+// server — сторона плагина: разворачивает пачки записей в вызовы Processor.
+type server struct {
+	pluginv1.UnimplementedProcessorServiceServer
+	impl Processor
+}
+
+func (s *server) Describe(context.Context, *pluginv1.DescribeRequest) (*pluginv1.DescribeResponse, error) {
+	info := s.impl.Describe()
+	res := &pluginv1.DescribeResponse{
+		Name:             info.Name,
+		Version:          info.Version,
+		OnEntry:          info.OnEntry,
+		OnUploadComplete: info.OnUploadComplete,
+	}
+	for _, f := range info.ExportFormats {
+		res.ExportFormats = append(res.ExportFormats, &pluginv1.ExportFormat{
+			Name:        f.Name,
+			ContentType: f.ContentType,
+			Extension:   f.Extension,
+			Description: f.Description,
+		})
+	}
+	return res, nil
+}
+
+func (s *server) OnEntry(ctx context.Context, req *pluginv1.OnEntryRequest) (*pluginv1.OnEntryResponse, error) {
+	logs, err := decodeLogs(req.GetEntries())
+	if err != nil {
+		return nil, err
+	}
+	for i := range logs {
+		if logs[i], err = s.impl.OnEntry(ctx, logs[i]); err != nil {
+			return nil, err
+		}
+	}
+	entries, err := encodeLogs(logs)
+	if err != nil {
+		return nil, err
+	}
+	return &pluginv1.OnEntryResponse{Entries: entries}, nil
+}
+
+func (s *server) OnUploadComplete(ctx context.Context, req *pluginv1.OnUploadCompleteRequest) (*pluginv1.OnUploadCompleteResponse, error) {
+	ev := log.UploadComplete{Workspace: req.GetWorkspace()}
+	if err := json.Unmarshal(req.GetUpload(), &ev.Upload); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(req.GetSummary(), &ev.Summary); err != nil {
+		return nil, err
+	}
+	if err := s.impl.OnUploadComplete(ctx, ev); err != nil {
+		return nil, err
+	}
+	return &pluginv1.OnUploadCompleteResponse{}, nil
+}
+
+func (s *server) Export(ctx context.Context, req *pluginv1.ExportRequest) (*pluginv1.ExportResponse, error) {
+	logs, err := decodeLogs(req.GetEntries())
+	if err != nil {
+		return nil, err
+	}
+	data, err := s.impl.Export(ctx, req.GetFormat(), logs)
+	if err != nil {
+		return nil, err
+	}
+	return &pluginv1.ExportResponse{Data: data}, nil
+}
+
+// remote — сторона хоста: вызовы плагина через gRPC.
+type remote struct {
+	client pluginv1.ProcessorServiceClient
+}
+
+func (r *remote) describe(ctx context.Context) (Info, error) {
+	res, err := r.client.Describe(ctx, &pluginv1.DescribeRequest{})
+	if err != nil {
+		return Info{}, err
+	}
+	info := Info{
+		Name:             res.GetName(),
+		Version:          res.GetVersion(),
+		OnEntry:          res.GetOnEntry(),
+		OnUploadComplete: res.GetOnUploadComplete(),
+		ExportFormats:    []ExportFormat{},
+	}
+	for _, f := range res.GetExportFormats() {
+		info.ExportFormats = append(info.ExportFormats, ExportFormat{
+			Name:        f.GetName(),
+			ContentType: f.GetContentType(),
+			Extension:   f.GetExtension(),
+			Description: f.GetDescription(),
+			Plugin:      info.Name,
+		})
+	}
+	return info, nil
+}
+
+func (r *remote) onEntries(ctx context.Context, logs []log.Log) ([]log.Log, error) {
+	entries, err := encodeLogs(logs)
+	if err != nil {
+		return nil, err
+	}
+	res, err := r.client.OnEntry(ctx, &pluginv1.OnEntryRequest{Entries: entries})
+	if err != nil {
+		return nil, err
+	}
+	return decodeLogs(res.GetEntries())
+}
+
+func (r *remote) uploadComplete(ctx context.Context, ev log.UploadComplete) error {
+	upload, err := json.Marshal(ev.Upload)
+	if err != nil {
+		return err
+	}
+	summary, err := json.Marshal(ev.Summary)
+	if err != nil {
+		return err
+	}
+	_, err = r.client.OnUploadComplete(ctx, &pluginv1.OnUploadCompleteRequest{
+		Workspace: ev.Workspace,
+		Upload:    upload,
+		Summary:   summary,
+	})
+	return err
+}
+
+func (r *remote) export(ctx context.Context, format string, logs []log.Log) ([]byte, error) {
+	entries, err := encodeLogs(logs)
+	if err != nil {
+		return nil, err
+	}
+	res, err := r.client.Export(ctx, &pluginv1.ExportRequest{Format: format, Entries: entries})
+	if err != nil {
+		return nil, err
+	}
+	return res.GetData(), nil
+}
+
+func encodeLogs(logs []log.Log) ([][]byte, error) {
+	entries := make([][]byte, len(logs))
+	for i := range logs {
+		b, err := json.Marshal(logs[i])
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = b
+	}
+	return entries, nil
+}
+
+func decodeLogs(entries [][]byte) ([]log.Log, error) {
+	logs := make([]log.Log, len(entries))
+	for i, b := range entries {
+		if err := json.Unmarshal(b, &logs[i]); err != nil {
+			return nil, err
+		}
+	}
+	return logs, nil
+}
@@ -0,0 +1,390 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	goplugin "github.com/hashicorp/go-plugin"
+	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown export format")
+	errDown          = errors.New("plugin is down")
+)
+
+const (
+	entryBatch = 500 // записей в одном вызове OnEntry
+	minBackoff = time.Second
+	maxBackoff = time.Minute
+)
+
+// Manager запускает плагины из каталога и вызывает их хуки; реализует
+// log.Processor. Упавший или зависший плагин пропускается и
+// перезапускается при следующем вызове, не чаще чем позволяет backoff.
+type Manager struct {
+	timeout time.Duration
+	plugins []*instance
+	pending sync.WaitGroup // OnUploadComplete в фоне
+}
+
+type instance struct {
+	path    string
+	timeout time.Duration // запуск процесса и Describe
+	logger  hclog.Logger
+
+	mu        sync.Mutex
+	starting  chan struct{} // закрывается по окончании запуска
+	client    *goplugin.Client
+	remote    *remote
+	info      Info
+	started   bool
+	restarts  int
+	failures  int // подряд, для backoff
+	retryAt   time.Time
+	lastErr   string
+	lastErrAt time.Time
+}
+
+// Status — состояние плагина для GET /admin/plugins.
+type Status struct {
+	Info
+	Path        string     `json:"path"`
+	Running     bool       `json:"running"`
+	Restarts    int        `json:"restarts"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+}
+
+// NewManager запускает все исполняемые файлы каталога dir. Плагин, который
+// не удалось запустить, остаётся в списке с ошибкой; пустой dir — без
+// плагинов.
+func NewManager(dir string, timeout time.Duration) (*Manager, error) {
+	m := &Manager{timeout: timeout}
+	if dir == "" {
+		return m, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("plugins: %w", err)
+	}
+	for _, e := range entries {
+		fi, err := e.Info()
+		if err != nil || !fi.Mode().IsRegular() || fi.Mode().Perm()&0o111 == 0 {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		p := &instance{
+			path:    path,
+			timeout: timeout,
+			info:    Info{Name: e.Name(), ExportFormats: []ExportFormat{}},
+			logger: hclog.New(&hclog.LoggerOptions{
+				Name:   "plugin",
+				Level:  hclog.Info,
+				Output: os.Stderr,
+			}),
+		}
+		m.plugins = append(m.plugins, p)
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		_, err = p.get(ctx)
+		cancel()
+		if err != nil {
+			slog.Error("plugin failed to start", "path", path, "err", err)
+			continue
+		}
+		slog.Info("plugin started", "name", p.info.Name, "version", p.info.Version, "path", path)
+	}
+	return m, nil
+}
+
+// get возвращает клиент плагина, при необходимости (пере)запуская процесс.
+// Запуск идёт без блокировки, чтобы Status и describe не ждали его;
+// параллельные вызовы дожидаются уже начатого запуска.
+func (p *instance) get(ctx context.Context) (*remote, error) {
+	p.mu.Lock()
+	for p.starting != nil {
+		starting := p.starting
+		p.mu.Unlock()
+		select {
+		case <-starting:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: still starting: %w", errDown, ctx.Err())
+		}
+		p.mu.Lock()
+	}
+	if p.client != nil && !p.client.Exited() {
+		defer p.mu.Unlock()
+		return p.remote, nil
+	}
+	if p.client != nil {
+		p.stopLocked(errors.New("plugin process exited"))
+	}
+	if time.Now().Before(p.retryAt) {
+		defer p.mu.Unlock()
+		return nil, fmt.Errorf("%w until %s: %s", errDown, p.retryAt.Format(time.RFC3339), p.lastErr)
+	}
+	starting := make(chan struct{})
+	p.starting = starting
+	p.mu.Unlock()
+
+	client, r, info, err := p.start(ctx)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.starting = nil
+	close(starting)
+	if client != nil {
+		if p.started {
+			p.restarts++
+		}
+		p.started = true
+	}
+	if err != nil {
+		if client != nil {
+			client.Kill()
+		}
+		p.failLocked(err)
+		return nil, err
+	}
+	p.client, p.remote, p.info = client, r, info
+	return r, nil
+}
+
+// start запускает процесс плагина и запрашивает Describe. client не nil,
+// если процесс успел запуститься.
+func (p *instance) start(ctx context.Context) (*goplugin.Client, *remote, Info, error) {
+	client := goplugin.NewClient(&goplugin.ClientConfig{
+		HandshakeConfig:  Handshake,
+		Plugins:          goplugin.PluginSet{pluginName: &grpcPlugin{}},
+		Cmd:              exec.Command(p.path),
+		AllowedProtocols: []goplugin.Protocol{goplugin.ProtocolGRPC},
+		Logger:           p.logger,
+		StartTimeout:     p.timeout,
+	})
+	r, err := dispense(client)
+	if err != nil {
+		client.Kill()
+		return nil, nil, Info{}, err
+	}
+	info, err := r.describe(ctx)
+	if err != nil {
+		return client, nil, Info{}, err
+	}
+	return client, r, info, nil
+}
+
+func dispense(client *goplugin.Client) (*remote, error) {
+	rpc, err := client.Client()
+	if err != nil {
+		return nil, err
+	}
+	raw, err := rpc.Dispense(pluginName)
+	if err != nil {
+		return nil, err
+	}
+	r, ok := raw.(*remote)
+	if !ok {
+		return nil, fmt.Errorf("unexpected plugin type %T", raw)
+	}
+	return r, nil
+}
+
+// fail записывает ошибку вызова. Сбой процесса или превышение времени
+// останавливает плагин до следующей попытки; ошибку самого хука — нет.
+func (p *instance) fail(err error) {
+	if errors.Is(err, errDown) {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled:
+		p.stopLocked(err)
+	default:
+		p.lastErr, p.lastErrAt = err.Error(), time.Now()
+	}
+}
+
+func (p *instance) ok() {
+	p.mu.Lock()
+	p.failures = 0
+	p.mu.Unlock()
+}
+
+func (p *instance) stopLocked(err error) {
+	if p.client != nil {
+		p.client.Kill()
+	}
+	p.client, p.remote = nil, nil
+	p.failLocked(err)
+}
+
+func (p *instance) failLocked(err error) {
+	p.lastErr, p.lastErrAt = err.Error(), time.Now()
+	backoff := min(minBackoff<<min(p.failures, 6), maxBackoff)
+	p.failures++
+	p.retryAt = time.Now().Add(backoff)
+}
+
+func (p *instance) describe() Info {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.info
+}
+
+// ProcessEntries пропускает записи через OnEntry всех плагинов по очереди.
+// При ошибке плагина его изменения текущей пачки отбрасываются.
+func (m *Manager) ProcessEntries(ctx context.Context, logs []log.Log) []log.Log {
+	for _, p := range m.plugins {
+		if !p.describe().OnEntry {
+			continue
+		}
+		for start := 0; start < len(logs); start += entryBatch {
+			batch := logs[start:min(start+entryBatch, len(logs))]
+			out, err := m.onEntries(ctx, p, batch)
+			if err != nil {
+				slog.Warn("plugin OnEntry failed, entries left unchanged",
+					"plugin", p.describe().Name, "err", err)
+				p.fail(err)
+				break
+			}
+			copy(batch, out)
+		}
+	}
+	return logs
+}
+
+func (m *Manager) onEntries(ctx context.Context, p *instance, batch []log.Log) ([]log.Log, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+	r, err := p.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out, err := r.onEntries(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+	if len(out) != len(batch) {
+		return nil, fmt.Errorf("OnEntry returned %d entries for %d", len(out), len(batch))
+	}
+	// Положение записи в файле плагин менять не может
+	for i := range out {
+		out[i].Id = batch[i].Id
+		out[i].LineNo = batch[i].LineNo
+		out[i].ByteOffset = batch[i].ByteOffset
+		out[i].ParseBodies()
+	}
+	p.ok()
+	return out, nil
+}
+
+// UploadComplete уведомляет плагины в фоне, не задерживая загрузку.
+func (m *Manager) UploadComplete(_ context.Context, ev log.UploadComplete) {
+	for _, p := range m.plugins {
+		if !p.describe().OnUploadComplete {
+			continue
+		}
+		m.pending.Go(func() {
+			ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+			defer cancel()
+			r, err := p.get(ctx)
+			if err == nil {
+				err = r.uploadComplete(ctx, ev)
+			}
+			if err != nil {
+				slog.Warn("plugin OnUploadComplete failed", "plugin", p.describe().Name, "err", err)
+				p.fail(err)
+				return
+			}
+			p.ok()
+		})
+	}
+}
+
+// Formats — форматы выгрузки, объявленные плагинами.
+func (m *Manager) Formats() []ExportFormat {
+	formats := []ExportFormat{}
+	for _, p := range m.plugins {
+		formats = append(formats, p.describe().ExportFormats...)
+	}
+	sort.Slice(formats, func(i, j int) bool { return formats[i].Name < formats[j].Name })
+	return formats
+}
+
+// Export выгружает записи форматом плагина; формат ищется среди
+// объявленных в Describe.
+func (m *Manager) Export(ctx context.Context, format string, logs []log.Log) ([]byte, ExportFormat, error) {
+	for _, p := range m.plugins {
+		info := p.describe()
+		i := slices.IndexFunc(info.ExportFormats, func(f ExportFormat) bool { return f.Name == format })
+		if i < 0 {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(ctx, m.timeout)
+		defer cancel()
+		r, err := p.get(ctx)
+		if err == nil {
+			var data []byte
+			if data, err = r.export(ctx, format, logs); err == nil {
+				p.ok()
+				return data, info.ExportFormats[i], nil
+			}
+		}
+		p.fail(err)
+		return nil, ExportFormat{}, fmt.Errorf("plugin %s: %w", info.Name, err)
+	}
+	return nil, ExportFormat{}, fmt.Errorf("%w %q", ErrUnknownFormat, format)
+}
+
+func (m *Manager) Status() []Status {
+	res := make([]Status, 0, len(m.plugins))
+	for _, p := range m.plugins {
+		p.mu.Lock()
+		s := Status{
+			Info:      p.info,
+			Path:      p.path,
+			Running:   p.client != nil && !p.client.Exited(),
+			Restarts:  p.restarts,
+			LastError: p.lastErr,
+		}
+		if !p.lastErrAt.IsZero() {
+			at := p.lastErrAt
+			s.LastErrorAt = &at
+		}
+		p.mu.Unlock()
+		res = append(res, s)
+	}
+	return res
+}
+
+// Close дожидается фоновых вызовов и останавливает процессы плагинов.
+func (m *Manager) Close() {
+	m.pending.Wait()
+	for _, p := range m.plugins {
+		p.mu.Lock()
+		for p.starting != nil {
+			starting := p.starting
+			p.mu.Unlock()
+			<-starting
+			p.mu.Lock()
+		}
+		if p.client != nil {
+			p.client.Kill()
+			p.client, p.remote = nil, nil
+		}
+		p.mu.Unlock()
+	}
+}
@@ -0,0 +1,91 @@
+// Пакет plugin — плагины-обработчики логов на hashicorp/go-plugin поверх
+// gRPC. Плагин — отдельный исполняемый файл в каталоге plugins.dir:
+//
+//	func main() {
+//		plugin.Serve(&tagger{})
+//	}
+//
+// где tagger встраивает plugin.Base и переопределяет нужные хуки. Сбой или
+// зависание плагина не останавливает загрузку: записи остаются как были,
+// а процесс плагина перезапускается позже (см. Manager).
+package plugin
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/hashicorp/go-hclog"
+	goplugin "github.com/hashicorp/go-plugin"
+	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+)
+
+// Handshake защищает от запуска посторонних программ как плагинов и от
+// несовместимых версий протокола.
+var Handshake = goplugin.HandshakeConfig{
+	ProtocolVersion:  1,
+	MagicCookieKey:   "TFLOGS_PLUGIN",
+	MagicCookieValue: "c1b1d0f7-processor",
+}
+
+const pluginName = "processor"
+
+var ErrUnsupported = errors.New("not supported by plugin")
+
+type ExportFormat struct {
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Extension   string `json:"extension"`
+	Description string `json:"description,omitempty"`
+	Plugin      string `json:"plugin,omitempty"`
+}
+
+// Info описывает плагин; хуки без флага хост не вызывает.
+type Info struct {
+	Name             string         `json:"name"`
+	Version          string         `json:"version"`
+	OnEntry          bool           `json:"on_entry"`
+	OnUploadComplete bool           `json:"on_upload_complete"`
+	ExportFormats    []ExportFormat `json:"export_formats"`
+}
+
+// Processor реализуется плагином.
+type Processor interface {
+	Describe() Info
+	// OnEntry меняет запись при загрузке, например добавляет Labels.
+	OnEntry(ctx context.Context, l log.Log) (log.Log, error)
+	OnUploadComplete(ctx context.Context, ev log.UploadComplete) error
+	// Export выгружает записи в одном из форматов из Describe.
+	Export(ctx context.Context, format string, logs []log.Log) ([]byte, error)
+}
+
+// Base — реализация по умолчанию для встраивания: хуки ничего не делают.
+type Base struct{}
+
+func (Base) OnEntry(_ context.Context, l log.Log) (log.Log, error) { return l, nil }
+
+func (Base) OnUploadComplete(context.Context, log.UploadComplete) error { return nil }
+
+func (Base) Export(context.Context, string, []log.Log) ([]byte, error) {
+	return nil, ErrUnsupported
+}
+
+// Serve запускает плагин; вызывается из main исполняемого файла плагина.
+func Serve(p Processor) {
+	goplugin.Serve(&goplugin.ServeConfig{
+		HandshakeConfig: Handshake,
+		Plugins:         goplugin.PluginSet{pluginName: &grpcPlugin{impl: p}},
+		GRPCServer:      goplugin.DefaultGRPCServer,
+	})
+}
+
+// Logger — журнал плагина: записи в формате hclog хост показывает со
+// своими уровнями, остальной вывод stderr — только на уровне debug.
+func Logger(name string) hclog.Logger {
+	return hclog.New(&hclog.LoggerOptions{
+		Name:       name,
+		Level:      hclog.Trace,
+		Output:     os.Stderr,
+		JSONFormat: true,
+	})
+}
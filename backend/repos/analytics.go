@@ -0,0 +1,22 @@
+package repos
+
+import (
+	"context"
+
+	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+)
+
+// GetEndpointAnalytics считает статистику эндпоинтов API облака по
+// загрузкам запуска runID (пусто — по всем загрузкам пространства).
+func (r *LogRepo) GetEndpointAnalytics(ctx context.Context, runID string) ([]log.EndpointStats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ws, err := r.workspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids, logs := ws.runLogs(runID)
+	ws.touch(ids...)
+	return log.EndpointAnalytics(logs), nil
+}
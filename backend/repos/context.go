@@ -0,0 +1,87 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+)
+
+// GetLogContext возвращает соседние строки записи из того же файла, а при
+// window > 0 — записи всех загрузок того же запуска в окне ±window. В обоих
+// режимах берётся не больше before/after ближайших записей.
+func (r *LogRepo) GetLogContext(
+	ctx context.Context,
+	id string,
+	before, after int,
+	window time.Duration,
+) (log.LogContext, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ws, err := r.workspace(ctx)
+	if err != nil {
+		return log.LogContext{}, err
+	}
+
+	l, ok := ws.store[id]
+	if !ok {
+		return log.LogContext{}, fmt.Errorf("log %s: %w", id, log.ErrNotFound)
+	}
+	ws.touch(l.UploadID)
+	res := log.LogContext{Entry: *l, Before: []log.Log{}, After: []log.Log{}}
+	if window > 0 {
+		ws.windowContext(&res, before, after, window)
+		return res, nil
+	}
+
+	logs := ws.uploadLogs(l.UploadID)
+	idx := slices.IndexFunc(logs, func(e log.Log) bool { return e.Id == id })
+	if idx < 0 {
+		return res, nil
+	}
+	res.Before = append(res.Before, logs[max(0, idx-before):idx]...)
+	res.After = append(res.After, logs[idx+1:min(len(logs), idx+1+after)]...)
+	return res, nil
+}
+
+func (ws *workspace) windowContext(res *log.LogContext, before, after int, window time.Duration) {
+	at, ok := log.Time(res.Entry)
+	if !ok {
+		return
+	}
+	runID := res.Entry.UploadID
+	if up, ok := ws.uploads[res.Entry.UploadID]; ok {
+		runID = up.RunID
+	}
+	from, to := at.Add(-window), at.Add(window)
+	_, logs := ws.runLogs(runID)
+	for _, e := range logs {
+		if e.Id == res.Entry.Id {
+			continue
+		}
+		t, ok := log.Time(e)
+		if !ok || t.Before(from) || t.After(to) {
+			continue
+		}
+		if t.Before(at) {
+			res.Before = append(res.Before, e)
+		} else {
+			res.After = append(res.After, e)
+		}
+	}
+	byTime := func(entries []log.Log) {
+		sort.SliceStable(entries, func(i, j int) bool {
+			ti, _ := log.Time(entries[i])
+			tj, _ := log.Time(entries[j])
+			return ti.Before(tj)
+		})
+	}
+	byTime(res.Before)
+	byTime(res.After)
+	res.Before = res.Before[max(0, len(res.Before)-before):]
+	res.After = res.After[:min(len(res.After), after)]
+}
@@ -0,0 +1,43 @@
+package repos
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+)
+
+func TestGetLogContextWindowCaps(t *testing.T) {
+	r := NewLogRepo().(*LogRepo)
+	uploadLines(t, r, logLines(0, 10))
+	logs, err := r.GetAllLogs(context.Background(), log.ExportFilters{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var id string
+	for _, l := range logs {
+		if l.At_message == "line 5" {
+			id = l.Id
+		}
+	}
+
+	res, err := r.GetLogContext(context.Background(), id, 2, 3, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages := func(logs []log.Log) string {
+		var m []string
+		for _, l := range logs {
+			m = append(m, strings.TrimPrefix(l.At_message, "line "))
+		}
+		return strings.Join(m, ",")
+	}
+	if got := messages(res.Before); got != "3,4" {
+		t.Errorf("before = %s, want 3,4", got)
+	}
+	if got := messages(res.After); got != "6,7,8" {
+		t.Errorf("after = %s, want 6,7,8", got)
+	}
+}
@@ -0,0 +1,127 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+)
+
+func (r *LogRepo) GetCorruptedLogs(ctx context.Context, uploadID string) ([]log.CorruptedLine, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ws, err := r.workspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []log.CorruptedLine{}
+	for _, c := range ws.corruptedLogs {
+		if uploadID != "" && c.UploadID != uploadID {
+			continue
+		}
+		result = append(result, c)
+	}
+	return result, nil
+}
+
+// ResolveCorruptedLog принимает исправленную вручную строку и ставит запись
+// на место исходной строки в файле: восстановленная автоматически запись
+// заменяется, пропущенная — вставляется.
+func (r *LogRepo) ResolveCorruptedLog(ctx context.Context, id string, line string) (log.Log, error) {
+	entry, err := log.ParseLine([]byte(line))
+	if err != nil {
+		return log.Log{}, fmt.Errorf("%w: %s", log.ErrInvalidLine, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ws, err := r.workspace(ctx)
+	if err != nil {
+		return log.Log{}, err
+	}
+
+	idx := slices.IndexFunc(ws.corruptedLogs, func(c log.CorruptedLine) bool { return c.ID == id })
+	if idx < 0 {
+		return log.Log{}, fmt.Errorf("corrupted line %s: %w", id, log.ErrNotFound)
+	}
+	c := &ws.corruptedLogs[idx]
+	logs, ok := ws.files[c.UploadID]
+	if !ok {
+		return log.Log{}, fmt.Errorf("upload %s: %w", c.UploadID, log.ErrNotFound)
+	}
+
+	if c.LogID != "" {
+		if old, ok := ws.store[c.LogID]; ok {
+			if entry.Id == "" {
+				entry.Id = old.Id
+			}
+			entry.SetState(old.TriageState())
+			entry.Assignee = old.Assignee
+			delete(ws.store, old.Id)
+		}
+	}
+	if entry.Id == "" {
+		entry.Id = c.ID
+	}
+	if entry.State == "" {
+		entry.SetState(log.StateUnread)
+	}
+	entry.TemplateID = ws.templates.Add(entry.At_message)
+	entry.UploadID = c.UploadID
+	entry.LineNo = c.Line
+	entry.ByteOffset = c.Offset
+
+	pos := slices.IndexFunc(logs, func(l log.Log) bool { return c.LogID != "" && l.Id == c.LogID })
+	if pos >= 0 {
+		logs[pos] = entry
+	} else {
+		pos = slices.IndexFunc(logs, func(l log.Log) bool { return l.LineNo > c.Line })
+		if pos < 0 {
+			pos = len(logs)
+		}
+		logs = slices.Insert(logs, pos, entry)
+		ws.files[c.UploadID] = logs
+		ws.reindexFile(c.UploadID)
+	}
+	ws.store[entry.Id] = &logs[pos]
+
+	// Списки строк файлов: прежняя запись заменяется, новая встаёт по
+	// номеру строки
+	if c.LogID != "" && c.LogID != entry.Id {
+		for _, up := range ws.uploads {
+			if i := slices.Index(up.lines, c.LogID); i >= 0 {
+				up.lines[i] = entry.Id
+			}
+		}
+	}
+	if up, ok := ws.uploads[c.UploadID]; ok {
+		if !slices.Contains(up.lines, entry.Id) {
+			i := slices.IndexFunc(up.lines, func(id string) bool {
+				l, ok := ws.store[id]
+				return ok && l.LineNo > c.Line
+			})
+			if i < 0 {
+				i = len(up.lines)
+			}
+			up.lines = slices.Insert(up.lines, i, entry.Id)
+		}
+		up.Lines = len(logs)
+	}
+
+	c.Resolved = true
+	c.RepairedText = line
+	c.LogID = entry.Id
+	return entry, nil
+}
+
+// reindexFile обновляет указатели store после изменения слайса файла.
+func (ws *workspace) reindexFile(uploadID string) {
+	logs := ws.files[uploadID]
+	for i := range logs {
+		ws.store[logs[i].Id] = &logs[i]
+	}
+}
@@ -0,0 +1,83 @@
+package repos
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+
+	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+)
+
+func (r *LogRepo) GetDiagnostics(ctx context.Context) ([]log.DiagnosticGroup, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ws, err := r.workspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct{ code, resType string }
+	groups := make(map[key]*log.DiagnosticGroup)
+	for _, l := range ws.store {
+		if l.Diag == nil || strings.ToLower(l.Diagnostic_severity) != "error" {
+			continue
+		}
+		k := key{l.Diag.ErrorCode, log.ResourceType(*l)}
+		g, ok := groups[k]
+		if !ok {
+			g = &log.DiagnosticGroup{
+				ErrorCode:    k.code,
+				ResourceType: k.resType,
+				HTTPStatuses: make(map[int]int),
+				Summaries:    []string{},
+				Attributes:   []string{},
+				Addresses:    []string{},
+				FirstSeen:    l.At_timestamp,
+				LastSeen:     l.At_timestamp,
+			}
+			groups[k] = g
+		}
+		g.Count++
+		if l.Diag.HTTPStatus != 0 {
+			g.HTTPStatuses[l.Diag.HTTPStatus]++
+		}
+		summary := strings.TrimSpace(l.Diagnostic_summary)
+		if summary != "" && !slices.Contains(g.Summaries, summary) {
+			g.Summaries = append(g.Summaries, summary)
+		}
+		if l.Diag.AttributePath != "" && !slices.Contains(g.Attributes, l.Diag.AttributePath) {
+			g.Attributes = append(g.Attributes, l.Diag.AttributePath)
+		}
+		for _, addr := range l.StateAddresses {
+			if !slices.Contains(g.Addresses, addr) {
+				g.Addresses = append(g.Addresses, addr)
+			}
+		}
+		if l.At_timestamp < g.FirstSeen {
+			g.FirstSeen = l.At_timestamp
+		}
+		if l.At_timestamp > g.LastSeen {
+			g.LastSeen = l.At_timestamp
+		}
+		g.LogIDs = append(g.LogIDs, l.Id)
+	}
+
+	result := make([]log.DiagnosticGroup, 0, len(groups))
+	for _, g := range groups {
+		sort.Strings(g.LogIDs)
+		sort.Strings(g.Addresses)
+		result = append(result, *g)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		if result[i].ErrorCode != result[j].ErrorCode {
+			return result[i].ErrorCode < result[j].ErrorCode
+		}
+		return result[i].ResourceType < result[j].ResourceType
+	})
+	return result, nil
+}
@@ -0,0 +1,62 @@
+package repos
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+)
+
+// Буфер событий подписчика; если подписчик не успевает его разбирать,
+// новые события для него теряются.
+const subscriberBuffer = 16
+
+type subscriber struct {
+	workspace string
+	events    chan log.UploadEvent
+}
+
+// Subscribe сообщает о новых загрузках пространства из ctx, пока ctx не
+// отменён; после отмены канал закрывается.
+func (r *LogRepo) Subscribe(ctx context.Context) (<-chan log.UploadEvent, error) {
+	r.mu.RLock()
+	ws, err := r.workspace(ctx)
+	r.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &subscriber{workspace: ws.ID, events: make(chan log.UploadEvent, subscriberBuffer)}
+	r.subMu.Lock()
+	r.subs[s] = struct{}{}
+	r.subMu.Unlock()
+	context.AfterFunc(ctx, func() {
+		r.subMu.Lock()
+		delete(r.subs, s)
+		r.subMu.Unlock()
+		close(s.events)
+	})
+	return s.events, nil
+}
+
+// publish вызывается под r.mu, записи копируются до снятия блокировки.
+func (r *LogRepo) publish(ws *workspace, up *upload, logs []log.Log) {
+	r.subMu.Lock()
+	defer r.subMu.Unlock()
+
+	var ev *log.UploadEvent
+	for s := range r.subs {
+		if s.workspace != ws.ID {
+			continue
+		}
+		if ev == nil {
+			ev = &log.UploadEvent{Workspace: ws.ID, Upload: up.info(), Logs: slices.Clone(logs)}
+		}
+		select {
+		case s.events <- *ev:
+		default:
+			slog.Warn("subscriber is too slow, upload event dropped", "workspace", ws.ID, "upload", up.ID)
+		}
+	}
+}
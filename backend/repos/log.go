@@ -2,7 +2,10 @@ package repos
 
 import (
 	"bytes"
+	"cmp"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -18,93 +21,216 @@ import (
 const timeFormat = "2006-01-02T15:04:05.000000-07:00"
 
 type LogRepo struct {
-	mu            sync.RWMutex
-	store         map[string]*log.Log  // id -> Log
-	files         map[string][]log.Log // ID файла -> логи
-	corruptedLogs []string
+	mu         sync.RWMutex
+	workspaces map[string]*workspace // ID пространства -> данные
+
+	subMu sync.Mutex
+	subs  map[*subscriber]struct{}
+
+	processor log.Processor
+}
+
+type Option func(r *LogRepo)
+
+// WithProcessor подключает обработку записей при загрузке (плагины).
+func WithProcessor(p log.Processor) Option {
+	return func(r *LogRepo) {
+		r.processor = p
+	}
 }
 
-func NewLogRepo() log.Repo {
-	return &LogRepo{
-		store:         make(map[string]*log.Log),
-		files:         make(map[string][]log.Log),
-		corruptedLogs: []string{},
+func NewLogRepo(opts ...Option) log.Repo {
+	r := &LogRepo{
+		workspaces: map[string]*workspace{
+			log.DefaultWorkspace: newDefaultWorkspace(),
+		},
+		subs: make(map[*subscriber]struct{}),
+	}
+	for _, opt := range opts {
+		opt(r)
 	}
+	return r
 }
 
 func (r *LogRepo) UploadFile(
 	ctx context.Context,
 	fileData []byte,
-	fileName string,
+	opts log.UploadOptions,
 ) (log.FileUploadResult, error) {
+	sum := sha256.Sum256(fileData)
+	fileHash := hex.EncodeToString(sum[:])
+	// Повтор уже загруженного файла и файл больше квоты отсекаются до
+	// разбора и плагинов
+	r.mu.Lock()
+	ws, err := r.workspace(ctx)
+	if err != nil {
+		r.mu.Unlock()
+		return log.FileUploadResult{}, err
+	}
+	res, dup := ws.duplicateUpload(fileHash)
+	quotaErr := ws.checkUploadBytes(len(fileData))
+	r.mu.Unlock()
+	if dup {
+		return res, nil
+	}
+	if quotaErr != nil {
+		return log.FileUploadResult{}, quotaErr
+	}
+
 	logs, corruptedLogs, err := log.LoadLogs(bytes.NewReader(fileData))
 	if err != nil {
 		return log.FileUploadResult{}, err
 	}
+	// Плагины работают вне блокировки: они могут быть медленными
+	if r.processor != nil {
+		logs = r.processor.ProcessEntries(ctx, logs)
+	}
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	if ws, err = r.workspace(ctx); err != nil {
+		return log.FileUploadResult{}, err
+	}
+	// Пока шёл разбор, тот же файл могли загрузить параллельно
+	if res, dup := ws.duplicateUpload(fileHash); dup {
+		return res, nil
+	}
+
+	// Квоту могли изменить, пока шёл разбор
+	if err := ws.checkUploadBytes(len(fileData)); err != nil {
+		return log.FileUploadResult{}, err
+	}
+	if q := ws.Quota.MaxLines; q > 0 {
+		added := 0
+		for _, l := range logs {
+			if _, ok := ws.store[l.Id]; !ok {
+				added++
+			}
+		}
+		if len(ws.store)+added > q {
+			return log.FileUploadResult{}, fmt.Errorf("%d stored + %d new lines exceeds limit %d: %w",
+				len(ws.store), added, q, log.ErrQuotaExceeded)
+		}
+	}
+
+	// ID строк детерминированы, поэтому уже известные строки (например,
+	// начало дозаписанного лога) пропускаем
 	fileID := uuid.NewString()
+	fresh := make([]log.Log, 0, len(logs))
+	lines := make([]string, 0, len(logs))
+	for i := range logs {
+		lines = append(lines, logs[i].Id)
+		// Ошибки сравниваем по всему файлу, включая уже известные строки
+		if known, ok := ws.store[logs[i].Id]; ok {
+			logs[i] = *known
+			continue
+		}
+		logs[i].TemplateID = ws.templates.Add(logs[i].At_message)
+		logs[i].UploadID = fileID
+		logs[i].SetState(log.StateUnread)
+		fresh = append(fresh, logs[i])
+	}
+	full := logs
+	duplicates := len(logs) - len(fresh)
+	logs = fresh
+	ws.state.Annotate(logs)
 	for i := range logs {
-		if logs[i].Id == "" {
-			logs[i].Id = uuid.NewString()
+		ws.store[logs[i].Id] = &logs[i]
+	}
+	ws.files[fileID] = logs
+	ws.fileHashes[fileHash] = fileID
+
+	known := make(map[string]bool, len(ws.corruptedLogs))
+	for _, c := range ws.corruptedLogs {
+		known[c.ID] = true
+	}
+	corrupted := 0
+	for _, c := range corruptedLogs {
+		if known[c.ID] {
+			continue
 		}
-		r.store[logs[i].Id] = &logs[i]
+		c.UploadID = fileID
+		ws.corruptedLogs = append(ws.corruptedLogs, c)
+		corrupted++
+	}
+
+	up := &upload{
+		Upload: log.Upload{
+			ID:          fileID,
+			FileName:    opts.FileName,
+			SHA256:      fileHash,
+			TFWorkspace: opts.TFWorkspace,
+			RunID:       cmp.Or(opts.RunID, fileID),
+			UploadedAt:  time.Now(),
+			Lines:       len(logs),
+			Corrupted:   corrupted,
+			Duplicates:  duplicates,
+			SizeBytes:   memoryFactor * int64(len(fileData)) * int64(len(logs)) / int64(max(1, len(full))),
+		},
+	}
+	up.lines = lines
+	up.viewed.Store(up.UploadedAt.UnixNano())
+	ws.addUpload(up, full)
+	r.publish(ws, up, logs)
+	if r.processor != nil {
+		r.processor.UploadComplete(ctx, log.UploadComplete{
+			Workspace: ws.ID,
+			Upload:    up.info(),
+			Summary:   log.Summarize(logs, 10),
+		})
 	}
-	r.files[fileID] = logs
-	r.corruptedLogs = append(r.corruptedLogs, corruptedLogs...)
 	return log.FileUploadResult{
-		ID:     fileID,
-		Status: "parsed",
+		ID:          fileID,
+		Status:      "parsed",
+		TFWorkspace: up.TFWorkspace,
+		Lines:       up.Lines,
+		Corrupted:   up.Corrupted,
+		Duplicates:  up.Duplicates,
+		Novel:       up.novel.New,
+		Disappeared: up.novel.Disappeared,
 	}, nil
 }
 
+// checkUploadBytes проверяет размер файла по квоте пространства.
+func (ws *workspace) checkUploadBytes(size int) error {
+	if q := ws.Quota.MaxUploadBytes; q > 0 && int64(size) > q {
+		return fmt.Errorf("upload of %d bytes exceeds limit %d: %w", size, q, log.ErrQuotaExceeded)
+	}
+	return nil
+}
+
+// duplicateUpload — результат повторной загрузки уже известного файла.
+func (ws *workspace) duplicateUpload(fileHash string) (log.FileUploadResult, bool) {
+	id, ok := ws.fileHashes[fileHash]
+	if !ok {
+		return log.FileUploadResult{}, false
+	}
+	up := ws.uploads[id]
+	return log.FileUploadResult{
+		ID:          id,
+		Status:      "duplicate",
+		DuplicateOf: id,
+		TFWorkspace: up.TFWorkspace,
+		Lines:       up.Lines,
+		Corrupted:   up.Corrupted,
+		Novel:       []log.ErrorFingerprint{},
+		Disappeared: []log.ErrorFingerprint{},
+	}, true
+}
+
 func (r *LogRepo) GetLogs(ctx context.Context, filters log.ExportFilters) ([]log.Log, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	var filtered []log.Log
-	// Фильтрация
-	for _, l := range r.store {
-		if filters.TFResourceType != "" && l.Tf_resource_type != filters.TFResourceType {
-			continue
-		}
-		if filters.Level != "" {
-			levelStr := strings.ToLower(l.At_level)
-			if levelStr == "" {
-				levelStr = levelToStr(l.Level)
-			}
-			if strings.ToLower(filters.Level) != levelStr {
-				continue
-			}
-		}
-		if filters.TimestampFrom != "" && l.Timestamp < filters.TimestampFrom {
-			continue
-		}
-		if filters.TimestampTo != "" && l.Timestamp > filters.TimestampTo {
-			continue
-		}
-		if filters.Search != "" {
-			b, _ := json.Marshal(l)
-			if !bytes.Contains(bytes.ToLower(b), []byte(strings.ToLower(filters.Search))) {
-				continue
-			}
-		}
-		filtered = append(filtered, *l)
+	ws, err := r.workspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	filtered, err := ws.matchingLogs(filters)
+	if err != nil {
+		return nil, err
 	}
-	// Сортировка по @timestamp
-	sort.Slice(filtered, func(i, j int) bool {
-		iDate, err := time.Parse(timeFormat, filtered[i].At_timestamp)
-		if err != nil {
-			fmt.Println(err.Error())
-		}
-		jDate, err := time.Parse(timeFormat, filtered[j].At_timestamp)
-		if err != nil {
-			fmt.Println(err.Error())
-		}
-		return iDate.After(jDate)
-	})
 	// Пагинация
 	page := 1
 	limit := 50
@@ -119,9 +245,117 @@ func (r *LogRepo) GetLogs(ctx context.Context, filters log.ExportFilters) ([]log
 		return []log.Log{}, nil
 	}
 	end := min(start+limit, len(filtered))
+	for _, l := range filtered[start:end] {
+		ws.touch(l.UploadID)
+	}
 	return filtered[start:end], nil
 }
 
+// GetAllLogs возвращает все записи под фильтры без пагинации — один
+// согласованный срез для потоковой выдачи.
+func (r *LogRepo) GetAllLogs(ctx context.Context, filters log.ExportFilters) ([]log.Log, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ws, err := r.workspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	filtered, err := ws.matchingLogs(filters)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range filtered {
+		ws.touch(l.UploadID)
+	}
+	return filtered, nil
+}
+
+// matchingLogs — копии записей под фильтры, новые первыми.
+func (ws *workspace) matchingLogs(filters log.ExportFilters) ([]log.Log, error) {
+	query, err := parseFilterQuery(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	type match struct {
+		l  *log.Log
+		at time.Time
+	}
+	var matches []match
+	for _, l := range ws.store {
+		if ws.matchFilters(l, filters, query) {
+			// Неразобранное время — нулевое, такие записи идут последними
+			at, _ := time.Parse(timeFormat, l.At_timestamp)
+			matches = append(matches, match{l, at})
+		}
+	}
+	// Сортировка по @timestamp
+	sort.Slice(matches, func(i, j int) bool { return matches[i].at.After(matches[j].at) })
+	filtered := make([]log.Log, len(matches))
+	for i, m := range matches {
+		filtered[i] = *m.l
+	}
+	return filtered, nil
+}
+
+// parseFilterQuery разбирает filters.Query; ошибка синтаксиса — ErrInvalid.
+func parseFilterQuery(filters log.ExportFilters) (*log.Query, error) {
+	query, err := log.ParseQuery(filters.Query)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", log.ErrInvalid, err)
+	}
+	return query, nil
+}
+
+func (ws *workspace) matchFilters(l *log.Log, filters log.ExportFilters, query *log.Query) bool {
+	if filters.RunID != "" {
+		if up, ok := ws.uploads[l.UploadID]; !ok || up.RunID != filters.RunID {
+			return false
+		}
+	}
+	if filters.TFResourceType != "" && l.Tf_resource_type != filters.TFResourceType {
+		return false
+	}
+	if filters.TemplateID != "" && l.TemplateID != filters.TemplateID {
+		return false
+	}
+	if filters.TFReqID != "" && l.Tf_req_id != filters.TFReqID {
+		return false
+	}
+	if filters.State != "" && string(l.TriageState()) != strings.ToLower(filters.State) {
+		return false
+	}
+	if filters.Assignee != "" && l.Assignee != filters.Assignee {
+		return false
+	}
+	if filters.Level != "" {
+		if strings.ToLower(filters.Level) != logLevel(l) {
+			return false
+		}
+	}
+	if filters.TimestampFrom != "" && l.Timestamp < filters.TimestampFrom {
+		return false
+	}
+	if filters.TimestampTo != "" && l.Timestamp > filters.TimestampTo {
+		return false
+	}
+	if filters.Search != "" {
+		b, _ := json.Marshal(l)
+		if !bytes.Contains(bytes.ToLower(b), []byte(strings.ToLower(filters.Search))) {
+			return false
+		}
+	}
+	return query.Match(l)
+}
+
+func logLevel(l *log.Log) string {
+	if l.At_level != "" {
+		return strings.ToLower(l.At_level)
+	}
+	return levelToStr(l.Level)
+}
+
 func levelToStr(level int) string {
 	switch level {
 	case 0:
@@ -139,19 +373,30 @@ func (r *LogRepo) GetLogByID(ctx context.Context, id string) (log.Log, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	l, ok := r.store[id]
+	ws, err := r.workspace(ctx)
+	if err != nil {
+		return log.Log{}, err
+	}
+
+	l, ok := ws.store[id]
 	if !ok {
 		return log.Log{}, errors.New("log not found")
 	}
+	ws.touch(l.UploadID)
 	return *l, nil
 }
 
 func (r *LogRepo) MarkLogsRead(ctx context.Context, ids []string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+
+	ws, err := r.workspace(ctx)
+	if err != nil {
+		return err
+	}
 	for _, id := range ids {
-		if _, ok := r.store[id]; ok {
-			r.store[id].Read = !r.store[id].Read
+		if l, ok := ws.store[id]; ok && l.TriageState() == log.StateUnread {
+			l.SetState(log.StateRead)
 		}
 	}
 	return nil
@@ -161,8 +406,13 @@ func (r *LogRepo) GetGroupByReqID(ctx context.Context, tfReqID string) ([]log.Lo
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
+	ws, err := r.workspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	var group []log.Log
-	for _, l := range r.store {
+	for _, l := range ws.store {
 		if l.Tf_req_id == tfReqID {
 			group = append(group, *l)
 		}
@@ -174,8 +424,13 @@ func (r *LogRepo) GetTimelineEntries(ctx context.Context) ([]log.TimelineEntry,
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
+	ws, err := r.workspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	entriesMap := make(map[string]log.TimelineEntry)
-	for _, l := range r.store {
+	for _, l := range ws.store {
 		if l.Tf_req_id == "" || l.At_timestamp == "" {
 			continue
 		}
@@ -228,9 +483,14 @@ func (r *LogRepo) GetMetrics(ctx context.Context) (log.Metrics, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
+	ws, err := r.workspace(ctx)
+	if err != nil {
+		return log.Metrics{}, err
+	}
+
 	var metrics log.Metrics
 	metrics.Levels = make(map[string]int)
-	for _, l := range r.store {
+	for _, l := range ws.store {
 		sev := strings.ToLower(l.Diagnostic_severity)
 		switch sev {
 		case "error":
@@ -255,6 +515,41 @@ func (r *LogRepo) ExportLogs(ctx context.Context, filters log.ExportFilters) ([]
 	return json.MarshalIndent(logs, "", "  ")
 }
 
+// GetHTTPTransactions собирает транзакции записей, подходящих под фильтры:
+// фильтр по ответу (статус, тело) возвращает и запрос той же транзакции.
+func (r *LogRepo) GetHTTPTransactions(ctx context.Context, filters log.ExportFilters) ([]log.HTTPTransaction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ws, err := r.workspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query, err := parseFilterQuery(filters)
+	if err != nil {
+		return nil, err
+	}
+	matched := make(map[string]bool)
+	for _, l := range ws.store {
+		if l.Tf_http_trans_id != "" && ws.matchFilters(l, filters, query) {
+			matched[l.Tf_http_trans_id] = true
+		}
+	}
+	var logs []log.Log
+	for _, id := range ws.uploadOrder {
+		n := len(logs)
+		for _, l := range ws.files[id] {
+			if matched[l.Tf_http_trans_id] {
+				logs = append(logs, l)
+			}
+		}
+		if len(logs) > n {
+			ws.touch(id)
+		}
+	}
+	return log.HTTPTransactions(logs), nil
+}
+
 func (r *LogRepo) SendExportToTelegram(
 	ctx context.Context,
 	chatID string,
@@ -262,7 +557,3 @@ func (r *LogRepo) SendExportToTelegram(
 ) error {
 	return errors.New("unimplemented")
 }
-
-func (r *LogRepo) GetCorruptedLogs(ctx context.Context) ([]string, error) {
-	return r.corruptedLogs, nil
-}
@@ -0,0 +1,106 @@
+package repos
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+)
+
+type countingProcessor struct{ calls int }
+
+func (p *countingProcessor) ProcessEntries(_ context.Context, logs []log.Log) []log.Log {
+	p.calls++
+	return logs
+}
+
+func (p *countingProcessor) UploadComplete(context.Context, log.UploadComplete) {}
+
+func TestUploadDuplicateSkipsProcessor(t *testing.T) {
+	proc := &countingProcessor{}
+	r := NewLogRepo(WithProcessor(proc)).(*LogRepo)
+	data := logLines(0, 5)
+	first := uploadLines(t, r, data)
+
+	res, err := r.UploadFile(context.Background(), data, log.UploadOptions{FileName: "again.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Status != "duplicate" || res.DuplicateOf != first || res.Lines != 5 {
+		t.Errorf("second upload = %+v, want duplicate of %s with 5 lines", res, first)
+	}
+	if proc.calls != 1 {
+		t.Errorf("ProcessEntries called %d times, want 1", proc.calls)
+	}
+}
+
+func TestGrownLogViewsSeeWholeFile(t *testing.T) {
+	r := NewLogRepo().(*LogRepo)
+	head := `{"@level":"info","@message":"Terraform version: 1.13.1","@timestamp":"2025-09-09T15:30:00.000000+03:00"}
+{"@level":"error","@message":"Error: quota exceeded","@timestamp":"2025-09-09T15:30:01.000000+03:00"}
+`
+	first := uploadLines(t, r, []byte(head))
+	grown := uploadLines(t, r, append([]byte(head), logLines(0, 3)...))
+
+	diff, err := r.DiffUploads(context.Background(), first, grown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.Config) != 0 {
+		t.Errorf("diff of a grown log reports config changes: %+v", diff.Config)
+	}
+	gate, err := r.EvaluateGate(context.Background(), grown, log.DefaultGateThresholds())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gate.Passed || gate.Counts.Errors != 1 {
+		t.Errorf("gate of a grown log = passed %v with %d errors, want failed with 1", gate.Passed, gate.Counts.Errors)
+	}
+	lc, err := r.GetLogContext(context.Background(), r.workspaces[log.DefaultWorkspace].uploads[grown].lines[2], 10, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lc.Before) != 2 || len(lc.After) != 2 {
+		t.Errorf("context in grown log: %d before, %d after, want 2 and 2", len(lc.Before), len(lc.After))
+	}
+}
+
+func TestUploadOverQuotaSkipsProcessor(t *testing.T) {
+	proc := &countingProcessor{}
+	r := NewLogRepo(WithProcessor(proc)).(*LogRepo)
+	r.workspaces[log.DefaultWorkspace].Quota.MaxUploadBytes = 10
+
+	_, err := r.UploadFile(context.Background(), logLines(0, 5), log.UploadOptions{FileName: "big.json"})
+	if !errors.Is(err, log.ErrQuotaExceeded) {
+		t.Fatalf("upload over quota: %v, want ErrQuotaExceeded", err)
+	}
+	if proc.calls != 0 {
+		t.Errorf("ProcessEntries called %d times for rejected upload", proc.calls)
+	}
+}
+
+func TestNovelErrorsComparePreviousRun(t *testing.T) {
+	r := NewLogRepo().(*LogRepo)
+	uploadRun := func(run, lines string) log.FileUploadResult {
+		res, err := r.UploadFile(context.Background(), []byte(lines), log.UploadOptions{TFWorkspace: "prod", RunID: run})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+	prev := uploadRun("build-1", `{"@level":"error","@message":"Error: quota exceeded"}`+"\n")
+	uploadRun("build-2", `{"@level":"error","@message":"Error: plan failed"}`+"\n")
+	apply := uploadRun("build-2", `{"@level":"info","@message":"Apply complete"}`+"\n")
+
+	report, err := r.GetNovelErrors(context.Background(), apply.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.PreviousUploadID != prev.ID {
+		t.Errorf("previous upload %s, want %s of the previous run", report.PreviousUploadID, prev.ID)
+	}
+	if len(report.Disappeared) != 1 || report.Disappeared[0].Template != "Error: quota exceeded" {
+		t.Errorf("disappeared = %+v, want the error of build-1", report.Disappeared)
+	}
+}
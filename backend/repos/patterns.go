@@ -0,0 +1,83 @@
+package repos
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+)
+
+const patternSamples = 3
+
+func (r *LogRepo) GetPatterns(ctx context.Context) ([]log.Pattern, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ws, err := r.workspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	patterns := make(map[string]*log.Pattern)
+	samples := make(map[string][]*log.Log) // самые ранние записи шаблона по порядку
+	for _, l := range ws.store {
+		if l.TemplateID == "" {
+			continue
+		}
+		p, ok := patterns[l.TemplateID]
+		if !ok {
+			tpl, _ := ws.templates.Get(l.TemplateID)
+			p = &log.Pattern{
+				TemplateID: l.TemplateID,
+				Levels:     make(map[string]int),
+				FirstSeen:  l.At_timestamp,
+				LastSeen:   l.At_timestamp,
+			}
+			if tpl != nil {
+				p.Template = tpl.String()
+			}
+			patterns[l.TemplateID] = p
+		}
+		p.Count++
+		p.Levels[logLevel(l)]++
+		if l.At_timestamp < p.FirstSeen {
+			p.FirstSeen = l.At_timestamp
+		}
+		if l.At_timestamp > p.LastSeen {
+			p.LastSeen = l.At_timestamp
+		}
+		samples[l.TemplateID] = addSample(samples[l.TemplateID], l)
+	}
+
+	result := make([]log.Pattern, 0, len(patterns))
+	for id, p := range patterns {
+		for _, l := range samples[id] {
+			p.Samples = append(p.Samples, *l)
+		}
+		result = append(result, *p)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].TemplateID < result[j].TemplateID
+	})
+	return result, nil
+}
+
+// addSample вставляет запись в отсортированные по времени примеры, если она
+// среди patternSamples самых ранних.
+func addSample(samples []*log.Log, l *log.Log) []*log.Log {
+	i := sort.Search(len(samples), func(i int) bool {
+		s := samples[i]
+		return l.At_timestamp < s.At_timestamp || l.At_timestamp == s.At_timestamp && l.Id < s.Id
+	})
+	if i == patternSamples {
+		return samples
+	}
+	if len(samples) == patternSamples {
+		samples = samples[:patternSamples-1]
+	}
+	return slices.Insert(samples, i, l)
+}
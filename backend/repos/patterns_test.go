@@ -0,0 +1,27 @@
+package repos
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGetPatternsEarliestSamples(t *testing.T) {
+	r := NewLogRepo().(*LogRepo)
+	uploadLines(t, r, logLines(5, 10))
+	uploadLines(t, r, logLines(0, 5))
+
+	patterns, err := r.GetPatterns(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(patterns) != 1 || patterns[0].Count != 10 {
+		t.Fatalf("patterns = %+v, want one of 10 entries", patterns)
+	}
+	var got []string
+	for _, l := range patterns[0].Samples {
+		got = append(got, l.At_message)
+	}
+	if len(got) != 3 || got[0] != "line 0" || got[1] != "line 1" || got[2] != "line 2" {
+		t.Errorf("samples = %v, want the three earliest lines", got)
+	}
+}
@@ -0,0 +1,92 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+)
+
+// AddPlan сохраняет план запуска runID; план того же запуска заменяется.
+func (r *LogRepo) AddPlan(ctx context.Context, data []byte, runID, fileName string) (log.Plan, error) {
+	if runID == "" {
+		return log.Plan{}, fmt.Errorf("run required: %w", log.ErrInvalid)
+	}
+	plan, err := log.ParsePlan(data)
+	if err != nil {
+		return log.Plan{}, err
+	}
+	plan.RunID, plan.FileName, plan.UploadedAt = runID, fileName, time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ws, err := r.workspace(ctx)
+	if err != nil {
+		return log.Plan{}, err
+	}
+	ws.plans[runID] = &plan
+	return plan, nil
+}
+
+// GetPlan возвращает план запуска с вызовами провайдера из его загрузок.
+func (r *LogRepo) GetPlan(ctx context.Context, runID string) (log.PlanReport, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ws, err := r.workspace(ctx)
+	if err != nil {
+		return log.PlanReport{}, err
+	}
+	plan, ok := ws.plans[runID]
+	if !ok {
+		return log.PlanReport{}, fmt.Errorf("plan %s: %w", runID, log.ErrNotFound)
+	}
+	ids, trace := ws.runLogs(runID)
+	report := log.CorrelatePlan(*plan, trace)
+	report.Uploads = append(report.Uploads, ids...)
+	ws.touch(ids...)
+	return report, nil
+}
+
+// GetGroupPlan находит в плане запуска изменение ресурса, над которым
+// выполнялся вызов tfReqID. Без плана Changes пуст.
+func (r *LogRepo) GetGroupPlan(ctx context.Context, tfReqID string) (log.GroupPlan, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ws, err := r.workspace(ctx)
+	if err != nil {
+		return log.GroupPlan{}, err
+	}
+	gp := log.GroupPlan{Changes: []log.PlannedResource{}}
+	var uploadID string
+	for _, l := range ws.store {
+		if l.Tf_req_id == tfReqID {
+			uploadID = l.UploadID
+			break
+		}
+	}
+	up, ok := ws.uploads[uploadID]
+	if !ok {
+		return gp, nil
+	}
+	gp.RunID = up.RunID
+	plan, ok := ws.plans[up.RunID]
+	if !ok {
+		return gp, nil
+	}
+	for _, call := range log.RPCCalls(ws.uploadLogs(uploadID)) {
+		if call.TFReqID == tfReqID {
+			gp.RPC, gp.Resource = call.RPC, call.Resource
+			break
+		}
+	}
+	if changes := plan.ResourceChanges(gp.Resource); changes != nil {
+		gp.Changes = changes
+	}
+	if len(gp.Changes) == 1 {
+		gp.Note = gp.RPC + " was for " + gp.Changes[0].Summary
+	}
+	return gp, nil
+}
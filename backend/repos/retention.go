@@ -0,0 +1,185 @@
+package repos
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+)
+
+func (r *LogRepo) ApplyRetention(ctx context.Context, p log.RetentionPolicy) (log.RetentionReport, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	type candidate struct {
+		ws *workspace
+		up *upload
+	}
+	now := time.Now()
+	report := log.RetentionReport{At: now, Evicted: []log.EvictedUpload{}}
+	evict := func(c candidate, reason string) int {
+		removed := c.ws.evict(c.up.ID)
+		report.Evicted = append(report.Evicted, log.EvictedUpload{
+			Workspace: c.ws.ID,
+			UploadID:  c.up.ID,
+			FileName:  c.up.FileName,
+			Entries:   removed,
+			Reason:    reason,
+		})
+		return removed
+	}
+
+	// evict меняет ws.uploadOrder, поэтому сначала собираем загрузки
+	var expired, candidates []candidate
+	for _, ws := range r.workspaces {
+		for _, id := range ws.uploadOrder {
+			up := ws.uploads[id]
+			switch {
+			case up.Pinned:
+			case p.MaxAge > 0 && now.Sub(up.UploadedAt) > p.MaxAge:
+				expired = append(expired, candidate{ws, up})
+			default:
+				candidates = append(candidates, candidate{ws, up})
+			}
+		}
+	}
+	for _, c := range expired {
+		evict(c, "max_age")
+	}
+
+	// Сначала вытесняются самые старые (или давно не просмотренные) загрузки
+	key := func(c candidate) int64 {
+		if p.Evict == log.EvictLRU {
+			return c.up.viewed.Load()
+		}
+		return c.up.UploadedAt.UnixNano()
+	}
+	sort.Slice(candidates, func(i, j int) bool { return key(candidates[i]) < key(candidates[j]) })
+
+	usage := r.usage()
+	entries, mem := usage.Entries, usage.MemoryBytes
+	for _, c := range candidates {
+		var reason string
+		switch {
+		case p.MaxEntries > 0 && entries > p.MaxEntries:
+			reason = "max_entries"
+		case p.MaxMemoryBytes > 0 && mem > p.MaxMemoryBytes:
+			reason = "max_memory"
+		default:
+			continue
+		}
+		mem -= c.up.SizeBytes
+		entries -= evict(c, reason)
+	}
+	report.Entries, report.MemoryBytes = entries, mem
+	return report, nil
+}
+
+// evict удаляет загрузку со всеми её записями, битыми строками и заметками
+// и возвращает число удалённых записей. Записи, которые есть и в файлах
+// других загрузок (см. upload.lines), переходят к старейшей из них.
+func (ws *workspace) evict(uploadID string) int {
+	up, ok := ws.uploads[uploadID]
+	if !ok {
+		return 0
+	}
+	owners := make(map[string]string) // ID записи -> новая загрузка
+	for _, l := range ws.files[uploadID] {
+		owners[l.Id] = ""
+	}
+	for _, id := range ws.uploadOrder {
+		if id == uploadID {
+			continue
+		}
+		for _, lineID := range ws.uploads[id].lines {
+			if owner, ok := owners[lineID]; ok && owner == "" {
+				owners[lineID] = id
+			}
+		}
+	}
+	moved := make(map[string][]log.Log)
+	removed := 0
+	for _, l := range ws.files[uploadID] {
+		if owner := owners[l.Id]; owner != "" {
+			l.UploadID = owner
+			moved[owner] = append(moved[owner], l)
+			continue
+		}
+		delete(ws.store, l.Id)
+		delete(ws.notes, log.NoteTarget{Kind: log.NoteTargetLog, ID: l.Id})
+		removed++
+	}
+	// Вместе с записями переходит и их доля памяти
+	owned := int64(max(1, len(ws.files[uploadID])))
+	for id, logs := range moved {
+		ws.files[id] = append(logs, ws.files[id]...)
+		ws.reindexFile(id)
+		receiver := ws.uploads[id]
+		receiver.Lines += len(logs)
+		receiver.Duplicates -= len(logs)
+		receiver.SizeBytes += up.SizeBytes * int64(len(logs)) / owned
+	}
+	delete(ws.files, uploadID)
+	delete(ws.uploads, uploadID)
+	delete(ws.fileHashes, up.SHA256)
+	ws.uploadOrder = slices.DeleteFunc(ws.uploadOrder, func(id string) bool { return id == uploadID })
+	ws.corruptedLogs = slices.DeleteFunc(ws.corruptedLogs, func(c log.CorruptedLine) bool {
+		return c.UploadID == uploadID
+	})
+
+	// План удаляется вместе с последней загрузкой запуска
+	if !slices.ContainsFunc(ws.uploadOrder, func(id string) bool { return ws.uploads[id].RunID == up.RunID }) {
+		delete(ws.plans, up.RunID)
+	}
+
+	// Заметки к группам удаляются, когда не осталось ни одной записи группы
+	for target := range ws.notes {
+		if target.Kind == log.NoteTargetGroup && !ws.noteTargetExists(target) {
+			delete(ws.notes, target)
+		}
+	}
+	return removed
+}
+
+func (ws *workspace) usage() log.WorkspaceUsage {
+	u := log.WorkspaceUsage{
+		Workspace: ws.ID,
+		Uploads:   len(ws.uploads),
+		Entries:   len(ws.store),
+		Corrupted: len(ws.corruptedLogs),
+	}
+	for _, up := range ws.uploads {
+		u.MemoryBytes += up.SizeBytes
+		if up.Pinned {
+			u.Pinned++
+		}
+	}
+	return u
+}
+
+// usage суммирует занятость всех пространств. Вызывается под r.mu.
+func (r *LogRepo) usage() log.Usage {
+	var total log.Usage
+	for _, ws := range r.workspaces {
+		u := ws.usage()
+		total.Workspaces = append(total.Workspaces, u)
+		total.Uploads += u.Uploads
+		total.Pinned += u.Pinned
+		total.Entries += u.Entries
+		total.Corrupted += u.Corrupted
+		total.MemoryBytes += u.MemoryBytes
+	}
+	sort.Slice(total.Workspaces, func(i, j int) bool {
+		return total.Workspaces[i].Workspace < total.Workspaces[j].Workspace
+	})
+	return total
+}
+
+func (r *LogRepo) GetUsage(ctx context.Context) (log.Usage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.usage(), nil
+}
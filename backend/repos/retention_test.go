@@ -0,0 +1,108 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+)
+
+func logLines(from, to int) []byte {
+	var b strings.Builder
+	for i := from; i < to; i++ {
+		fmt.Fprintf(&b, `{"@level":"info","@message":"line %d","@timestamp":"2025-09-09T15:31:%02d.000000+03:00"}`+"\n", i, i%60)
+	}
+	return []byte(b.String())
+}
+
+func uploadLines(t *testing.T, r *LogRepo, data []byte) string {
+	t.Helper()
+	res, err := r.UploadFile(context.Background(), data, log.UploadOptions{FileName: "apply.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.ID
+}
+
+func TestApplyRetentionMaxAgeEvictsAll(t *testing.T) {
+	r := NewLogRepo().(*LogRepo)
+	for i := range 3 {
+		uploadLines(t, r, logLines(i*10, i*10+10))
+	}
+	time.Sleep(2 * time.Millisecond)
+
+	report, err := r.ApplyRetention(context.Background(), log.RetentionPolicy{MaxAge: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Evicted) != 3 {
+		t.Fatalf("evicted %d uploads, want 3", len(report.Evicted))
+	}
+	if usage := r.usage(); usage.Uploads != 0 || usage.Entries != 0 {
+		t.Fatalf("usage after eviction: %+v", usage)
+	}
+}
+
+func TestApplyRetentionKeepsPinned(t *testing.T) {
+	r := NewLogRepo().(*LogRepo)
+	ctx := context.Background()
+	uploadLines(t, r, logLines(0, 5))
+	pinned := uploadLines(t, r, logLines(5, 10))
+	uploadLines(t, r, logLines(10, 15))
+	if err := r.PinUpload(ctx, pinned, true); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(2 * time.Millisecond)
+
+	if _, err := r.ApplyRetention(ctx, log.RetentionPolicy{MaxAge: time.Millisecond}); err != nil {
+		t.Fatal(err)
+	}
+	uploads, err := r.ListUploads(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(uploads) != 1 || uploads[0].ID != pinned {
+		t.Fatalf("uploads after eviction: %+v", uploads)
+	}
+}
+
+func TestEvictKeepsLinesOfLaterUploads(t *testing.T) {
+	r := NewLogRepo().(*LogRepo)
+	ctx := context.Background()
+	first := uploadLines(t, r, logLines(0, 10))
+	// Тот же лог после дозаписи: первые 10 строк уже известны
+	second := uploadLines(t, r, logLines(0, 20))
+
+	ws := r.workspaces[log.DefaultWorkspace]
+	size := ws.uploads[first].SizeBytes + ws.uploads[second].SizeBytes
+	if removed := ws.evict(first); removed != 0 {
+		t.Fatalf("removed %d entries, want 0", removed)
+	}
+	if got := ws.uploads[second].SizeBytes; got != size {
+		t.Errorf("second upload size %d after taking over lines, want %d", got, size)
+	}
+	if len(ws.store) != 20 || len(ws.files[second]) != 20 {
+		t.Fatalf("store %d, second upload %d entries, want 20", len(ws.store), len(ws.files[second]))
+	}
+	for _, l := range ws.files[second] {
+		if l.UploadID != second {
+			t.Fatalf("entry %s belongs to %s, want %s", l.At_message, l.UploadID, second)
+		}
+	}
+	for i := range ws.files[second] {
+		if l := &ws.files[second][i]; ws.store[l.Id] != l {
+			t.Fatalf("store entry %s does not point into the file", l.At_message)
+		}
+	}
+	logs, err := r.GetLogs(ctx, log.ExportFilters{Query: `@message = "line 0"`})
+	if err != nil || len(logs) != 1 {
+		t.Fatalf("line 0 after eviction: %v %v", logs, err)
+	}
+
+	if removed := ws.evict(second); removed != 20 || len(ws.store) != 0 {
+		t.Fatalf("removed %d, store %d, want 20 and 0", removed, len(ws.store))
+	}
+}
@@ -0,0 +1,151 @@
+package repos
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+)
+
+// Версия формата снимка; увеличивается при несовместимых изменениях.
+const snapshotVersion = 1
+
+type snapshot struct {
+	Version    int                 `json:"version"`
+	CreatedAt  time.Time           `json:"created_at"`
+	Workspaces []workspaceSnapshot `json:"workspaces"`
+}
+
+type workspaceSnapshot struct {
+	Workspace  log.Workspace       `json:"workspace"`
+	Uploads    []uploadSnapshot    `json:"uploads"` // в порядке загрузки
+	Corrupted  []log.CorruptedLine `json:"corrupted"`
+	Templates  *log.TemplateMiner  `json:"templates"`
+	SeenErrors []string            `json:"seen_errors"`
+	Notes      []log.Note          `json:"notes"`
+	Plans      []log.Plan          `json:"plans,omitempty"`
+	State      *log.State          `json:"state,omitempty"`
+}
+
+type uploadSnapshot struct {
+	Upload log.Upload                       `json:"upload"`
+	Logs   []log.Log                        `json:"logs"`
+	Errors map[string]*log.ErrorFingerprint `json:"errors"`
+	Novel  log.NovelReport                  `json:"novel"`
+	Lines  []string                         `json:"lines,omitempty"`
+}
+
+// Snapshot пишет состояние всех пространств в w в виде gzip-сжатого JSON.
+func (r *LogRepo) Snapshot(ctx context.Context, w io.Writer) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	snap := snapshot{Version: snapshotVersion, CreatedAt: time.Now()}
+	for _, ws := range r.workspaces {
+		wsSnap := workspaceSnapshot{
+			Workspace:  ws.Workspace,
+			Corrupted:  ws.corruptedLogs,
+			Templates:  ws.templates,
+			SeenErrors: []string{},
+			Notes:      []log.Note{},
+			State:      ws.state,
+		}
+		for _, id := range ws.uploadOrder {
+			up := ws.uploads[id]
+			wsSnap.Uploads = append(wsSnap.Uploads, uploadSnapshot{
+				Upload: up.info(),
+				Logs:   ws.files[id],
+				Errors: up.errors,
+				Novel:  up.novel,
+				Lines:  up.lines,
+			})
+		}
+		for fp := range ws.seenErrors {
+			wsSnap.SeenErrors = append(wsSnap.SeenErrors, fp)
+		}
+		for _, notes := range ws.notes {
+			wsSnap.Notes = append(wsSnap.Notes, notes...)
+		}
+		for _, plan := range ws.plans {
+			wsSnap.Plans = append(wsSnap.Plans, *plan)
+		}
+		slices.SortFunc(wsSnap.Plans, func(a, b log.Plan) int { return a.UploadedAt.Compare(b.UploadedAt) })
+		slices.SortFunc(wsSnap.Notes, func(a, b log.Note) int { return a.CreatedAt.Compare(b.CreatedAt) })
+		snap.Workspaces = append(snap.Workspaces, wsSnap)
+	}
+
+	zw := gzip.NewWriter(w)
+	if err := json.NewEncoder(zw).Encode(snap); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// Restore заменяет всё состояние репозитория содержимым снимка.
+func (r *LogRepo) Restore(ctx context.Context, rd io.Reader) error {
+	zr, err := gzip.NewReader(rd)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+	var snap snapshot
+	if err := json.NewDecoder(zr).Decode(&snap); err != nil {
+		return err
+	}
+	if snap.Version != snapshotVersion {
+		return fmt.Errorf("unsupported snapshot version %d", snap.Version)
+	}
+
+	workspaces := make(map[string]*workspace, len(snap.Workspaces))
+	for _, wsSnap := range snap.Workspaces {
+		ws := newWorkspace(wsSnap.Workspace)
+		if wsSnap.Templates != nil {
+			ws.templates = wsSnap.Templates
+		}
+		for _, us := range wsSnap.Uploads {
+			up := &upload{Upload: us.Upload, errors: us.Errors, novel: us.Novel, lines: us.Lines}
+			if up.lines == nil {
+				// Снимок без списка строк: файл состоит из своих записей
+				for _, l := range us.Logs {
+					up.lines = append(up.lines, l.Id)
+				}
+			}
+			up.viewed.Store(us.Upload.ViewedAt.UnixNano())
+			ws.uploads[up.ID] = up
+			ws.uploadOrder = append(ws.uploadOrder, up.ID)
+			ws.fileHashes[up.SHA256] = up.ID
+			for i := range us.Logs {
+				us.Logs[i].ParseBodies()
+			}
+			ws.files[up.ID] = us.Logs
+			ws.reindexFile(up.ID)
+		}
+		if wsSnap.Corrupted != nil {
+			ws.corruptedLogs = wsSnap.Corrupted
+		}
+		for _, fp := range wsSnap.SeenErrors {
+			ws.seenErrors[fp] = true
+		}
+		for _, n := range wsSnap.Notes {
+			ws.notes[n.Target] = append(ws.notes[n.Target], n)
+		}
+		ws.state = wsSnap.State
+		for _, plan := range wsSnap.Plans {
+			ws.plans[plan.RunID] = &plan
+		}
+		workspaces[ws.ID] = ws
+	}
+	if _, ok := workspaces[log.DefaultWorkspace]; !ok {
+		workspaces[log.DefaultWorkspace] = newDefaultWorkspace()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.workspaces = workspaces
+	return nil
+}
@@ -0,0 +1,46 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+)
+
+// SetState заменяет состояние terraform пространства и заново размечает
+// адресами ресурсов все загруженные записи.
+func (r *LogRepo) SetState(ctx context.Context, data []byte, fileName string) (log.State, error) {
+	state, err := log.ParseState(data)
+	if err != nil {
+		return log.State{}, err
+	}
+	state.FileName, state.UploadedAt = fileName, time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ws, err := r.workspace(ctx)
+	if err != nil {
+		return log.State{}, err
+	}
+	ws.state = &state
+	// Записи в store — указатели на элементы files, разметка видна и там
+	for _, logs := range ws.files {
+		state.Annotate(logs)
+	}
+	return state, nil
+}
+
+func (r *LogRepo) GetState(ctx context.Context) (log.State, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ws, err := r.workspace(ctx)
+	if err != nil {
+		return log.State{}, err
+	}
+	if ws.state == nil {
+		return log.State{}, fmt.Errorf("state: %w", log.ErrNotFound)
+	}
+	return *ws.state, nil
+}
@@ -0,0 +1,122 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+)
+
+// UpdateTriage меняет состояние и исполнителя записей из списка ids, а
+// если он пуст — всех записей под фильтр (без пагинации).
+func (r *LogRepo) UpdateTriage(
+	ctx context.Context,
+	ids []string,
+	filters *log.ExportFilters,
+	update log.TriageUpdate,
+) (int, error) {
+	if update.State != nil && !update.State.Valid() {
+		return 0, fmt.Errorf("unknown state %q: %w", *update.State, log.ErrInvalid)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ws, err := r.workspace(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	apply := func(l *log.Log) {
+		if update.State != nil {
+			l.SetState(*update.State)
+		}
+		if update.Assignee != nil {
+			l.Assignee = strings.TrimSpace(*update.Assignee)
+		}
+	}
+	updated := 0
+	if len(ids) > 0 {
+		for _, id := range ids {
+			if l, ok := ws.store[id]; ok {
+				apply(l)
+				updated++
+			}
+		}
+		return updated, nil
+	}
+	if filters == nil {
+		return 0, nil
+	}
+	// Пустые фильтры изменили бы все записи пространства одним запросом
+	if filters.Empty() {
+		return 0, fmt.Errorf("filters match every entry: %w", log.ErrInvalid)
+	}
+	query, err := parseFilterQuery(*filters)
+	if err != nil {
+		return 0, err
+	}
+	for _, l := range ws.store {
+		if ws.matchFilters(l, *filters, query) {
+			apply(l)
+			updated++
+		}
+	}
+	return updated, nil
+}
+
+func (r *LogRepo) AddNote(ctx context.Context, target log.NoteTarget, author, text string) (log.Note, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ws, err := r.workspace(ctx)
+	if err != nil {
+		return log.Note{}, err
+	}
+
+	if !ws.noteTargetExists(target) {
+		return log.Note{}, fmt.Errorf("%s %s: %w", target.Kind, target.ID, log.ErrNotFound)
+	}
+	note := log.Note{
+		ID:        uuid.NewString(),
+		Target:    target,
+		Author:    author,
+		Text:      text,
+		CreatedAt: time.Now(),
+	}
+	ws.notes[target] = append(ws.notes[target], note)
+	return note, nil
+}
+
+func (r *LogRepo) GetNotes(ctx context.Context, target log.NoteTarget) ([]log.Note, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ws, err := r.workspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ws.noteTargetExists(target) {
+		return nil, fmt.Errorf("%s %s: %w", target.Kind, target.ID, log.ErrNotFound)
+	}
+	return append([]log.Note{}, ws.notes[target]...), nil
+}
+
+func (ws *workspace) noteTargetExists(target log.NoteTarget) bool {
+	switch target.Kind {
+	case log.NoteTargetLog:
+		_, ok := ws.store[target.ID]
+		return ok
+	case log.NoteTargetGroup:
+		for _, l := range ws.store {
+			if l.Tf_req_id == target.ID {
+				return true
+			}
+		}
+	}
+	return false
+}
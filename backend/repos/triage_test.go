@@ -0,0 +1,22 @@
+package repos
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+)
+
+func TestUpdateTriageInvalid(t *testing.T) {
+	r := NewLogRepo().(*LogRepo)
+	uploadLines(t, r, logLines(0, 3))
+	state := log.TriageState("archived")
+	if _, err := r.UpdateTriage(context.Background(), nil, &log.ExportFilters{Query: "severity = info"}, log.TriageUpdate{State: &state}); !errors.Is(err, log.ErrInvalid) {
+		t.Errorf("unknown state: %v, want ErrInvalid", err)
+	}
+	assignee := "ops"
+	if _, err := r.UpdateTriage(context.Background(), nil, &log.ExportFilters{Limit: 10}, log.TriageUpdate{Assignee: &assignee}); !errors.Is(err, log.ErrInvalid) {
+		t.Errorf("filters matching everything: %v, want ErrInvalid", err)
+	}
+}
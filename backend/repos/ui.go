@@ -0,0 +1,111 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+)
+
+// GetUIReport собирает события terraform -json загрузки и сопоставляет их
+// с записями TF_LOG: загрузки traceID, если он задан, иначе самой
+// загрузки, загрузки того же запуска (run_id) или пересекающейся по времени.
+func (r *LogRepo) GetUIReport(ctx context.Context, uploadID, traceID string) (log.UIReport, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ws, err := r.workspace(ctx)
+	if err != nil {
+		return log.UIReport{}, err
+	}
+	up, ok := ws.uploads[uploadID]
+	if !ok {
+		return log.UIReport{}, fmt.Errorf("upload %s: %w", uploadID, log.ErrNotFound)
+	}
+	report := log.BuildUIReport(ws.uploadLogs(uploadID))
+	report.UploadID = uploadID
+	if report.Start == nil {
+		ws.touch(uploadID)
+		return report, nil
+	}
+	if traceID != "" {
+		if _, ok := ws.uploads[traceID]; !ok {
+			return log.UIReport{}, fmt.Errorf("upload %s: %w", traceID, log.ErrNotFound)
+		}
+	} else {
+		traceID = ws.traceUpload(up, *report.Start, *report.End)
+	}
+	if traceID != "" {
+		report.TraceUploadID = traceID
+		log.CorrelateUI(&report, ws.uploadLogs(traceID))
+		ws.touch(uploadID, traceID)
+	} else {
+		ws.touch(uploadID)
+	}
+	return report, nil
+}
+
+// traceUpload подбирает загрузку TF_LOG к выводу -json: сама загрузка, если
+// в ней есть вызовы провайдера, затем загрузки того же запуска, затем
+// любые — по наибольшему пересечению по времени.
+func (ws *workspace) traceUpload(up *upload, start, end time.Time) string {
+	if hasTrace(ws.uploadLogs(up.ID)) {
+		return up.ID
+	}
+	best, bestOverlap, bestSameRun := "", time.Duration(-1), false
+	for _, id := range ws.uploadOrder {
+		if id == up.ID {
+			continue
+		}
+		logs := ws.uploadLogs(id)
+		if !hasTrace(logs) {
+			continue
+		}
+		from, to, ok := timeSpan(logs)
+		if !ok {
+			continue
+		}
+		sameRun := ws.uploads[id].RunID == up.RunID
+		if to.After(end) {
+			to = end
+		}
+		if from.Before(start) {
+			from = start
+		}
+		overlap := to.Sub(from)
+		if !sameRun && overlap < 0 {
+			continue
+		}
+		if sameRun && !bestSameRun || sameRun == bestSameRun && overlap > bestOverlap {
+			best, bestOverlap, bestSameRun = id, overlap, sameRun
+		}
+	}
+	return best
+}
+
+func hasTrace(logs []log.Log) bool {
+	for _, l := range logs {
+		if l.Tf_req_id != "" && !log.IsUIEvent(l) {
+			return true
+		}
+	}
+	return false
+}
+
+func timeSpan(logs []log.Log) (start, end time.Time, ok bool) {
+	for _, l := range logs {
+		at, has := log.Time(l)
+		if !has {
+			continue
+		}
+		if !ok || at.Before(start) {
+			start = at
+		}
+		if !ok || at.After(end) {
+			end = at
+		}
+		ok = true
+	}
+	return start, end, ok
+}
@@ -0,0 +1,257 @@
+package repos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+)
+
+type upload struct {
+	log.Upload
+	errors map[string]*log.ErrorFingerprint // отпечаток -> агрегат
+	novel  log.NovelReport
+	viewed atomic.Int64 // unix-время последнего просмотра, обновляется под RLock
+	// ID всех записей файла по порядку, включая сохранённые раньше под
+	// другими загрузками (начало дозаписанного лога); files хранит только
+	// собственные записи загрузки
+	lines []string
+}
+
+// Распарсенная запись со строками, map'ами и индексами занимает в памяти
+// примерно втрое больше исходной JSON-строки.
+const memoryFactor = 3
+
+func (up *upload) info() log.Upload {
+	info := up.Upload
+	info.ViewedAt = time.Unix(0, up.viewed.Load())
+	return info
+}
+
+// touch отмечает просмотр записей загрузок для вытеснения по LRU.
+func (ws *workspace) touch(uploadIDs ...string) {
+	now := time.Now().UnixNano()
+	for _, id := range uploadIDs {
+		if up, ok := ws.uploads[id]; ok {
+			up.viewed.Store(now)
+		}
+	}
+}
+
+// uploadLogs — все записи файла загрузки по порядку.
+func (ws *workspace) uploadLogs(uploadID string) []log.Log {
+	up, ok := ws.uploads[uploadID]
+	if !ok {
+		return nil
+	}
+	logs := make([]log.Log, 0, len(up.lines))
+	for _, id := range up.lines {
+		if l, ok := ws.store[id]; ok {
+			logs = append(logs, *l)
+		}
+	}
+	return logs
+}
+
+// runLogs — записи загрузок запуска runID (пустой — всех загрузок) без
+// повторов и ID этих загрузок.
+func (ws *workspace) runLogs(runID string) ([]string, []log.Log) {
+	var (
+		ids  []string
+		logs []log.Log
+	)
+	seen := make(map[string]bool)
+	for _, id := range ws.uploadOrder {
+		up := ws.uploads[id]
+		if runID != "" && up.RunID != runID {
+			continue
+		}
+		ids = append(ids, id)
+		for _, lineID := range up.lines {
+			if l, ok := ws.store[lineID]; ok && !seen[lineID] {
+				seen[lineID] = true
+				logs = append(logs, *l)
+			}
+		}
+	}
+	return ids, logs
+}
+
+func (ws *workspace) fingerprintErrors(logs []log.Log) map[string]*log.ErrorFingerprint {
+	fps := make(map[string]*log.ErrorFingerprint)
+	for _, l := range logs {
+		fp := log.Fingerprint(l)
+		if fp == "" {
+			continue
+		}
+		e, ok := fps[fp]
+		if !ok {
+			e = &log.ErrorFingerprint{
+				Fingerprint:  fp,
+				Severity:     log.Severity(l),
+				ResourceType: log.ResourceType(l),
+				TFRPC:        l.Tf_rpc,
+				TemplateID:   l.TemplateID,
+				Summary:      strings.TrimSpace(l.Diagnostic_summary),
+				SampleLogID:  l.Id,
+			}
+			if tpl, ok := ws.templates.Get(l.TemplateID); ok {
+				e.Template = tpl.String()
+			}
+			if l.Diag != nil {
+				e.ErrorCode = l.Diag.ErrorCode
+			}
+			fps[fp] = e
+		}
+		e.Count++
+	}
+	return fps
+}
+
+// addUpload регистрирует загрузку и сравнивает её ошибки с прошлыми:
+// новые — не встречавшиеся ни в одной загрузке, пропавшие — бывшие в
+// предыдущем запуске того же workspace.
+func (ws *workspace) addUpload(up *upload, logs []log.Log) {
+	up.errors = ws.fingerprintErrors(logs)
+	up.novel = log.NovelReport{
+		UploadID:    up.ID,
+		TFWorkspace: up.TFWorkspace,
+		New:         []log.ErrorFingerprint{},
+		Disappeared: []log.ErrorFingerprint{},
+	}
+	for fp, e := range up.errors {
+		if !ws.seenErrors[fp] {
+			up.novel.New = append(up.novel.New, *e)
+		}
+	}
+	if prev := ws.previousUpload(up); prev != nil {
+		up.novel.PreviousUploadID = prev.ID
+		for fp, e := range prev.errors {
+			if _, ok := up.errors[fp]; !ok {
+				up.novel.Disappeared = append(up.novel.Disappeared, *e)
+			}
+		}
+	}
+	sortFingerprints(up.novel.New)
+	sortFingerprints(up.novel.Disappeared)
+
+	for fp := range up.errors {
+		ws.seenErrors[fp] = true
+	}
+	ws.uploads[up.ID] = up
+	ws.uploadOrder = append(ws.uploadOrder, up.ID)
+}
+
+// previousUpload — последняя загрузка другого запуска того же workspace;
+// загрузки своего запуска (план к этому apply) не в счёт.
+func (ws *workspace) previousUpload(cur *upload) *upload {
+	for i := len(ws.uploadOrder) - 1; i >= 0; i-- {
+		up := ws.uploads[ws.uploadOrder[i]]
+		if up != nil && up.TFWorkspace == cur.TFWorkspace && up.RunID != cur.RunID {
+			return up
+		}
+	}
+	return nil
+}
+
+func sortFingerprints(fps []log.ErrorFingerprint) {
+	sort.Slice(fps, func(i, j int) bool {
+		if fps[i].Severity != fps[j].Severity {
+			return fps[i].Severity == "error"
+		}
+		if fps[i].Count != fps[j].Count {
+			return fps[i].Count > fps[j].Count
+		}
+		return fps[i].Fingerprint < fps[j].Fingerprint
+	})
+}
+
+func (r *LogRepo) GetNovelErrors(ctx context.Context, uploadID string) (log.NovelReport, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ws, err := r.workspace(ctx)
+	if err != nil {
+		return log.NovelReport{}, err
+	}
+
+	up, ok := ws.uploads[uploadID]
+	if !ok {
+		return log.NovelReport{}, errors.New("upload not found")
+	}
+	ws.touch(uploadID)
+	return up.novel, nil
+}
+
+func (r *LogRepo) DiffUploads(ctx context.Context, baseID, targetID string) (log.RunDiff, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ws, err := r.workspace(ctx)
+	if err != nil {
+		return log.RunDiff{}, err
+	}
+
+	if _, ok := ws.uploads[baseID]; !ok {
+		return log.RunDiff{}, fmt.Errorf("upload %s not found", baseID)
+	}
+	if _, ok := ws.uploads[targetID]; !ok {
+		return log.RunDiff{}, fmt.Errorf("upload %s not found", targetID)
+	}
+	ws.touch(baseID, targetID)
+	diff := log.DiffRuns(ws.uploadLogs(baseID), ws.uploadLogs(targetID))
+	diff.Base, diff.Target = baseID, targetID
+	return diff, nil
+}
+
+func (r *LogRepo) EvaluateGate(ctx context.Context, uploadID string, thresholds log.GateThresholds) (log.GateReport, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ws, err := r.workspace(ctx)
+	if err != nil {
+		return log.GateReport{}, err
+	}
+	up, ok := ws.uploads[uploadID]
+	if !ok {
+		return log.GateReport{}, fmt.Errorf("upload %s: %w", uploadID, log.ErrNotFound)
+	}
+	ws.touch(uploadID)
+	return log.EvaluateGate(up.FileName, ws.uploadLogs(uploadID), thresholds), nil
+}
+
+func (r *LogRepo) ListUploads(ctx context.Context) ([]log.Upload, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ws, err := r.workspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]log.Upload, 0, len(ws.uploadOrder))
+	for _, id := range ws.uploadOrder {
+		result = append(result, ws.uploads[id].info())
+	}
+	return result, nil
+}
+
+func (r *LogRepo) PinUpload(ctx context.Context, id string, pinned bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ws, err := r.workspace(ctx)
+	if err != nil {
+		return err
+	}
+	up, ok := ws.uploads[id]
+	if !ok {
+		return fmt.Errorf("upload %s: %w", id, log.ErrNotFound)
+	}
+	up.Pinned = pinned
+	return nil
+}
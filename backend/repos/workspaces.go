@@ -0,0 +1,122 @@
+package repos
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"gitlab.com/paradaise1/t1-hackaton-terraform/domain/log"
+)
+
+// workspace хранит данные одного рабочего пространства. Блокировка общая —
+// LogRepo.mu.
+type workspace struct {
+	log.Workspace
+	store         map[string]*log.Log  // id -> Log
+	files         map[string][]log.Log // ID файла -> логи
+	corruptedLogs []log.CorruptedLine
+	templates     *log.TemplateMiner
+	uploads       map[string]*upload // ID файла -> метаданные загрузки
+	fileHashes    map[string]string  // SHA-256 файла -> ID файла
+	uploadOrder   []string
+	seenErrors    map[string]bool // отпечатки ошибок из всех прошлых загрузок
+	notes         map[log.NoteTarget][]log.Note
+	plans         map[string]*log.Plan // run_id -> план
+	state         *log.State           // последнее загруженное состояние terraform
+}
+
+func newWorkspace(meta log.Workspace) *workspace {
+	return &workspace{
+		Workspace:     meta,
+		store:         make(map[string]*log.Log),
+		files:         make(map[string][]log.Log),
+		corruptedLogs: []log.CorruptedLine{},
+		templates:     log.NewTemplateMiner(),
+		uploads:       make(map[string]*upload),
+		fileHashes:    make(map[string]string),
+		seenErrors:    make(map[string]bool),
+		notes:         make(map[log.NoteTarget][]log.Note),
+		plans:         make(map[string]*log.Plan),
+	}
+}
+
+func newDefaultWorkspace() *workspace {
+	return newWorkspace(log.Workspace{
+		ID:        log.DefaultWorkspace,
+		Name:      "Default",
+		CreatedAt: time.Now(),
+	})
+}
+
+func (ws *workspace) info() log.Workspace {
+	info := ws.Workspace
+	info.Lines = len(ws.store)
+	info.Uploads = len(ws.uploads)
+	return info
+}
+
+// workspace возвращает пространство из контекста запроса. Вызывается под r.mu.
+func (r *LogRepo) workspace(ctx context.Context) (*workspace, error) {
+	id := log.WorkspaceFromContext(ctx)
+	ws, ok := r.workspaces[id]
+	if !ok {
+		return nil, fmt.Errorf("workspace %s: %w", id, log.ErrNotFound)
+	}
+	return ws, nil
+}
+
+func (r *LogRepo) CreateWorkspace(ctx context.Context, meta log.Workspace) (log.Workspace, error) {
+	if !log.ValidWorkspaceID(meta.ID) {
+		return log.Workspace{}, fmt.Errorf("workspace id %q: %w", meta.ID, log.ErrInvalid)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.workspaces[meta.ID]; ok {
+		return log.Workspace{}, fmt.Errorf("workspace %s already exists: %w", meta.ID, log.ErrConflict)
+	}
+	meta.Name = cmp.Or(meta.Name, meta.ID)
+	meta.CreatedAt = time.Now()
+	ws := newWorkspace(meta)
+	r.workspaces[meta.ID] = ws
+	return ws.info(), nil
+}
+
+func (r *LogRepo) ListWorkspaces(ctx context.Context) ([]log.Workspace, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]log.Workspace, 0, len(r.workspaces))
+	for _, ws := range r.workspaces {
+		result = append(result, ws.info())
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+func (r *LogRepo) GetWorkspace(ctx context.Context, id string) (log.Workspace, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ws, ok := r.workspaces[id]
+	if !ok {
+		return log.Workspace{}, log.ErrNotFound
+	}
+	return ws.info(), nil
+}
+
+func (r *LogRepo) DeleteWorkspace(ctx context.Context, id string) error {
+	if id == log.DefaultWorkspace {
+		return fmt.Errorf("workspace %s cannot be deleted: %w", id, log.ErrConflict)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.workspaces[id]; !ok {
+		return log.ErrNotFound
+	}
+	delete(r.workspaces, id)
+	return nil
+}